@@ -0,0 +1,61 @@
+package main
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+)
+
+// arquivoSaida é um arquivo de um sink de arquivo, comprimido com gzip
+// quando Arquivos.Gzip está ligado.
+type arquivoSaida struct {
+	f  *os.File
+	gz *gzip.Writer
+}
+
+// criarArquivo cria (ou trunca) o arquivo nome dentro de Arquivos.Diretorio,
+// acrescentando .gz ao nome quando a compressão está ligada.
+func criarArquivo(cfg *Config, nome string) (*arquivoSaida, error) {
+	return criarArquivoEm(cfg.Arquivos.Diretorio, nome, cfg.Arquivos.Gzip)
+}
+
+// criarArquivoEm cria o arquivo nome dentro de diretorio, comprimido ou não
+// independentemente de Arquivos.Gzip. Serve aos formatos que comprimem
+// internamente, como o Parquet.
+func criarArquivoEm(diretorio, nome string, comprimir bool) (*arquivoSaida, error) {
+	if comprimir {
+		nome += ".gz"
+	}
+	caminho := filepath.Join(diretorio, nome)
+	if err := os.MkdirAll(filepath.Dir(caminho), 0o755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(caminho)
+	if err != nil {
+		return nil, err
+	}
+	a := &arquivoSaida{f: f}
+	if comprimir {
+		a.gz = gzip.NewWriter(f)
+	}
+	return a, nil
+}
+
+func (a *arquivoSaida) Write(p []byte) (int, error) {
+	if a.gz != nil {
+		return a.gz.Write(p)
+	}
+	return a.f.Write(p)
+}
+
+// Close conclui o fluxo gzip, se houver, e fecha o arquivo.
+func (a *arquivoSaida) Close() error {
+	if a.gz != nil {
+		if err := a.gz.Close(); err != nil {
+			a.f.Close()
+			return err
+		}
+	}
+	return a.f.Close()
+}
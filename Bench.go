@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Consulta é uma das consultas do comparativo (README, "Consultas Adaptadas").
+type Consulta struct {
+	Numero    int    `json:"numero"`
+	Descricao string `json:"descricao"`
+}
+
+// consultas segue a numeração e as descrições das seções "Consultas
+// Adaptadas" do README: as cinco agregações do comparativo original e a
+// busca pontual por CPF, que também entram no relatório e no compare.
+var consultas = []Consulta{
+	{1, "Total de vendas por ano"},
+	{2, "Produtos mais vendidos"},
+	{3, "Faturamento por estado"},
+	{4, "Clientes fidelizados por cidade"},
+	{5, "Lucro médio por setor"},
+	{consultaClientePorCPF, "Cliente por CPF"},
+}
+
+// consultaClientePorCPF é a busca por documento. O CPF procurado é lido do
+// próprio banco, do cliente codClienteConsulta, antes das medições.
+const (
+	consultaClientePorCPF = 6
+	codClienteConsulta    = 1
+)
+
+// Executor roda as consultas do comparativo num banco.
+type Executor interface {
+	Nome() string
+	Abrir(ctx context.Context) error
+	// Versao devolve a versão do servidor conectado.
+	Versao(ctx context.Context) (string, error)
+	// Preparar lê do banco o que a consulta precisa, fora das medições.
+	Preparar(ctx context.Context, c Consulta) error
+	// Metadados lê os metadados gravados pela geração dos dados do banco.
+	Metadados(ctx context.Context) (Metadados, error)
+	// Executar roda a consulta até consumir todo o resultado e devolve
+	// quantas linhas (ou grupos) ela produziu.
+	Executar(ctx context.Context, c Consulta) (int, error)
+	Fechar(ctx context.Context) error
+}
+
+// criarExecutores devolve os executores dos bancos listados em --sinks.
+func criarExecutores(cfg *Config) []Executor {
+	var executores []Executor
+	for _, nome := range cfg.Sinks {
+		switch nome {
+		case sinkMongoDB:
+			executores = append(executores, &executorMongo{cfg: cfg})
+		case sinkCassandra:
+			executores = append(executores, &executorCassandra{cfg: cfg})
+		case sinkPostgres:
+			executores = append(executores, &executorPostgres{cfg: cfg})
+		}
+	}
+	return executores
+}
+
+// Estatisticas resume as latências medidas de uma consulta.
+type Estatisticas struct {
+	Min     time.Duration `json:"min_ns"`
+	Mediana time.Duration `json:"mediana_ns"`
+	P95     time.Duration `json:"p95_ns"`
+	P99     time.Duration `json:"p99_ns"`
+	Max     time.Duration `json:"max_ns"`
+}
+
+// calcularEstatisticas usa percentis pelo método do posto mais próximo,
+// que sempre devolvem uma das amostras medidas.
+func calcularEstatisticas(amostras []time.Duration) Estatisticas {
+	if len(amostras) == 0 {
+		return Estatisticas{}
+	}
+	ordenadas := slices.Clone(amostras)
+	slices.Sort(ordenadas)
+
+	percentil := func(p int) time.Duration {
+		posto := (p*len(ordenadas) + 99) / 100 // teto de p% de n
+		return ordenadas[max(posto, 1)-1]
+	}
+	return Estatisticas{
+		Min:     ordenadas[0],
+		Mediana: percentil(50),
+		P95:     percentil(95),
+		P99:     percentil(99),
+		Max:     ordenadas[len(ordenadas)-1],
+	}
+}
+
+// ResultadoConsulta guarda as medições de uma consulta num banco.
+type ResultadoConsulta struct {
+	Consulta Consulta        `json:"consulta"`
+	Banco    string          `json:"banco"`
+	Linhas   int             `json:"linhas"`
+	Amostras []time.Duration `json:"amostras_ns"`
+	Estatisticas
+}
+
+// executarBench trata "bench": roda cada consulta em cada banco de --sinks,
+// descartando as execuções de aquecimento, imprime as latências e grava os
+// resultados em --bench-resultados.
+func executarBench(args []string) {
+	if len(args) > 0 && args[0] == "compare" {
+		executarComparacao(args[1:])
+		return
+	}
+
+	cfg, ok := configurar("bench", args)
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+
+	execucao := novaExecucaoBench(&cfg)
+	for _, executor := range criarExecutores(&cfg) {
+		if err := executor.Abrir(ctx); err != nil {
+			log.Fatalf("Erro ao conectar ao %s: %v", executor.Nome(), err)
+		}
+		execucao.registrarServidor(ctx, executor)
+
+		metadados, err := executor.Metadados(ctx)
+		if err != nil {
+			executor.Fechar(ctx)
+			log.Fatalf("Erro ao ler os metadados da geração no %s (gere ou carregue os dados de novo): %v", executor.Nome(), err)
+		}
+		if err := execucao.registrarConjunto(executor.Nome(), metadados); err != nil {
+			executor.Fechar(ctx)
+			log.Fatal(err)
+		}
+
+		for _, c := range consultas {
+			fmt.Printf("Consulta %d no %s...\n", c.Numero, executor.Nome())
+			resultado, err := medirConsulta(ctx, &cfg, executor, c)
+			if err != nil {
+				executor.Fechar(ctx)
+				log.Fatalf("Erro na consulta %d no %s: %v", c.Numero, executor.Nome(), err)
+			}
+			execucao.Resultados = append(execucao.Resultados, resultado)
+		}
+
+		if err := executor.Fechar(ctx); err != nil {
+			log.Printf("Erro ao fechar %s: %v", executor.Nome(), err)
+		}
+	}
+
+	imprimirResultados(execucao.Resultados)
+
+	arquivos, err := execucao.salvar(cfg.Bench.Resultados)
+	if err != nil {
+		log.Fatalf("Erro ao gravar resultados: %v", err)
+	}
+	fmt.Printf("\nResultados gravados em %s\n", strings.Join(arquivos, " e "))
+}
+
+// medirConsulta executa as rodadas de aquecimento e depois as medidas.
+func medirConsulta(ctx context.Context, cfg *Config, executor Executor, c Consulta) (ResultadoConsulta, error) {
+	if err := executor.Preparar(ctx, c); err != nil {
+		return ResultadoConsulta{}, err
+	}
+	for i := 0; i < cfg.Bench.Aquecimento; i++ {
+		if _, err := executor.Executar(ctx, c); err != nil {
+			return ResultadoConsulta{}, err
+		}
+	}
+
+	resultado := ResultadoConsulta{Consulta: c, Banco: executor.Nome()}
+	for i := 0; i < cfg.Bench.Repeticoes; i++ {
+		inicio := time.Now()
+		linhas, err := executor.Executar(ctx, c)
+		if err != nil {
+			return ResultadoConsulta{}, err
+		}
+		resultado.Amostras = append(resultado.Amostras, time.Since(inicio))
+		resultado.Linhas = linhas
+	}
+	// Uma busca pontual que não acha nada mede um caminho diferente do
+	// pretendido, em geral por causa de dados inconsistentes
+	if c.Numero == consultaClientePorCPF && resultado.Linhas == 0 {
+		return ResultadoConsulta{}, errors.New("o CPF procurado não foi encontrado")
+	}
+	resultado.Estatisticas = calcularEstatisticas(resultado.Amostras)
+	return resultado, nil
+}
+
+// imprimirResultados mostra uma linha por consulta e banco, em milissegundos.
+func imprimirResultados(resultados []ResultadoConsulta) {
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%.1f", float64(d)/float64(time.Millisecond))
+	}
+
+	fmt.Printf("\n%-34s %-10s %9s %9s %9s %9s %9s %7s\n",
+		"Consulta", "Banco", "min", "mediana", "p95", "p99", "max", "linhas")
+	for _, r := range resultados {
+		fmt.Printf("%-34s %-10s %9s %9s %9s %9s %9s %7d\n",
+			fmt.Sprintf("%d. %s", r.Consulta.Numero, r.Consulta.Descricao), r.Banco,
+			ms(r.Min), ms(r.Mediana), ms(r.P95), ms(r.P99), ms(r.Max), r.Linhas)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// executarComparacao trata "bench compare antigo.json novo.json [flags]":
+// compara as medições de cada consulta e banco presentes nos dois arquivos
+// e termina com código 1 se alguma ficou significativamente mais lenta.
+func executarComparacao(args []string) {
+	if len(args) < 2 {
+		log.Fatalf("Uso: bench compare antigo.json novo.json [flags]")
+	}
+	cfg, ok := configurar("bench compare", args[2:])
+	if !ok {
+		return
+	}
+
+	antiga, err := lerExecucaoBench(args[0])
+	if err != nil {
+		log.Fatalf("Erro ao ler resultados: %v", err)
+	}
+	nova, err := lerExecucaoBench(args[1])
+	if err != nil {
+		log.Fatalf("Erro ao ler resultados: %v", err)
+	}
+
+	if diferencas := diferencasConjunto(antiga.ConjuntoDados, nova.ConjuntoDados); len(diferencas) > 0 {
+		fmt.Printf("Atenção: os conjuntos de dados diferem (%s)\n\n", strings.Join(diferencas, ", "))
+	}
+
+	comparacoes := compararExecucoes(antiga, nova, &cfg.Bench)
+	imprimirComparacoes(comparacoes, &cfg.Bench)
+
+	for _, c := range comparacoes {
+		if c.Situacao == situacaoRegressao {
+			os.Exit(1)
+		}
+	}
+}
+
+// Situação de uma consulta entre duas execuções
+const (
+	situacaoRegressao = "regressão"
+	situacaoMelhora   = "melhora"
+	situacaoEstavel   = "sem mudança significativa"
+	situacaoAusente   = "ausente numa das execuções"
+	situacaoSemBase   = "mediana antiga zerada"
+)
+
+// Comparacao confronta as medições de uma consulta num banco.
+type Comparacao struct {
+	Consulta Consulta
+	Banco    string
+	Antiga   time.Duration // mediana
+	Nova     time.Duration // mediana
+	Variacao float64       // % da nova mediana em relação à antiga
+	P        float64       // p-valor do teste de Mann-Whitney
+	Situacao string
+}
+
+// compararExecucoes casa os resultados por consulta e banco. Uma variação
+// só conta como regressão (ou melhora) se passar do limite configurado e o
+// teste de Mann-Whitney rejeitar, ao nível Alfa, que as amostras venham da
+// mesma distribuição.
+func compararExecucoes(antiga, nova *ExecucaoBench, cfg *BenchConfig) []Comparacao {
+	type chave struct {
+		consulta int
+		banco    string
+	}
+	antigos := make(map[chave]ResultadoConsulta)
+	for _, r := range antiga.Resultados {
+		antigos[chave{r.Consulta.Numero, r.Banco}] = r
+	}
+
+	var comparacoes []Comparacao
+	vistos := make(map[chave]bool)
+	for _, r := range nova.Resultados {
+		k := chave{r.Consulta.Numero, r.Banco}
+		vistos[k] = true
+
+		anterior, ok := antigos[k]
+		if !ok {
+			comparacoes = append(comparacoes, Comparacao{Consulta: r.Consulta, Banco: r.Banco, Nova: r.Mediana, Situacao: situacaoAusente})
+			continue
+		}
+
+		c := Comparacao{
+			Consulta: r.Consulta,
+			Banco:    r.Banco,
+			Antiga:   anterior.Mediana,
+			Nova:     r.Mediana,
+			Situacao: situacaoSemBase,
+		}
+		if anterior.Mediana == 0 {
+			// Sem base para a variação percentual
+			comparacoes = append(comparacoes, c)
+			continue
+		}
+
+		c.Variacao = (float64(r.Mediana)/float64(anterior.Mediana) - 1) * 100
+		c.P = mannWhitney(anterior.Amostras, r.Amostras)
+		c.Situacao = situacaoEstavel
+		if c.P < cfg.Alfa {
+			switch {
+			case c.Variacao > cfg.LimiteRegressao:
+				c.Situacao = situacaoRegressao
+			case c.Variacao < -cfg.LimiteRegressao:
+				c.Situacao = situacaoMelhora
+			}
+		}
+		comparacoes = append(comparacoes, c)
+	}
+
+	for _, r := range antiga.Resultados {
+		if !vistos[chave{r.Consulta.Numero, r.Banco}] {
+			comparacoes = append(comparacoes, Comparacao{Consulta: r.Consulta, Banco: r.Banco, Antiga: r.Mediana, Situacao: situacaoAusente})
+		}
+	}
+	return comparacoes
+}
+
+// diferencasConjunto descreve o que muda entre os conjuntos de dados de
+// duas execuções.
+func diferencasConjunto(antigo, novo ConjuntoDados) []string {
+	var diferencas []string
+	if antigo.Semente != novo.Semente {
+		diferencas = append(diferencas, fmt.Sprintf("semente %d/%d", antigo.Semente, novo.Semente))
+	}
+	if antigo.Escala != novo.Escala {
+		diferencas = append(diferencas, fmt.Sprintf("escala %g/%g", antigo.Escala, novo.Escala))
+	}
+	if antigo.DataReferencia != novo.DataReferencia {
+		diferencas = append(diferencas, fmt.Sprintf("data de referência %s/%s", antigo.DataReferencia, novo.DataReferencia))
+	}
+	if antigo.Volumes != novo.Volumes {
+		diferencas = append(diferencas, fmt.Sprintf("volumes %+v/%+v", antigo.Volumes, novo.Volumes))
+	}
+	return diferencas
+}
+
+// mannWhitney devolve o p-valor bilateral do teste U de Mann-Whitney, pela
+// aproximação normal com correção de continuidade e de empates. Com poucas
+// amostras (menos de ~8 por lado) a aproximação é grosseira.
+func mannWhitney(a, b []time.Duration) float64 {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type amostra struct {
+		valor    time.Duration
+		primeira bool
+	}
+	todas := make([]amostra, 0, len(a)+len(b))
+	for _, v := range a {
+		todas = append(todas, amostra{v, true})
+	}
+	for _, v := range b {
+		todas = append(todas, amostra{v, false})
+	}
+	slices.SortFunc(todas, func(x, y amostra) int { return cmp.Compare(x.valor, y.valor) })
+
+	// Postos médios nos empates; somaEmpates acumula t³-t de cada grupo
+	var somaPostos, somaEmpates float64
+	for i := 0; i < len(todas); {
+		j := i
+		for j < len(todas) && todas[j].valor == todas[i].valor {
+			j++
+		}
+		posto := float64(i+j+1) / 2 // média dos postos i+1..j
+		for _, s := range todas[i:j] {
+			if s.primeira {
+				somaPostos += posto
+			}
+		}
+		t := float64(j - i)
+		somaEmpates += t*t*t - t
+		i = j
+	}
+
+	n := n1 + n2
+	u := somaPostos - n1*(n1+1)/2
+	media := n1 * n2 / 2
+	variancia := n1 * n2 / 12 * ((n + 1) - somaEmpates/(n*(n-1)))
+	if variancia <= 0 {
+		return 1
+	}
+
+	z := math.Max(math.Abs(u-media)-0.5, 0) / math.Sqrt(variancia)
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// imprimirComparacoes mostra uma linha por consulta e banco.
+func imprimirComparacoes(comparacoes []Comparacao, cfg *BenchConfig) {
+	ms := func(d time.Duration) string {
+		if d == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f", float64(d)/float64(time.Millisecond))
+	}
+
+	fmt.Printf("%-34s %-10s %10s %10s %9s %7s  %s\n",
+		"Consulta", "Banco", "antiga ms", "nova ms", "variação", "p", "situação")
+	for _, c := range comparacoes {
+		variacao, p := "-", "-"
+		if c.Situacao != situacaoAusente && c.Situacao != situacaoSemBase {
+			variacao = fmt.Sprintf("%+.1f%%", c.Variacao)
+			p = fmt.Sprintf("%.3f", c.P)
+		}
+		fmt.Printf("%-34s %-10s %10s %10s %9s %7s  %s\n",
+			fmt.Sprintf("%d. %s", c.Consulta.Numero, c.Consulta.Descricao), c.Banco,
+			ms(c.Antiga), ms(c.Nova), variacao, p, c.Situacao)
+	}
+	fmt.Printf("\nRegressão: mediana %.0f%% mais lenta com p < %g (Mann-Whitney)\n", cfg.LimiteRegressao, cfg.Alfa)
+}
+
+// lerExecucaoBench carrega um arquivo JSON gravado pelo bench.
+func lerExecucaoBench(caminho string) (*ExecucaoBench, error) {
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, err
+	}
+	var e ExecucaoBench
+	if err := json.Unmarshal(dados, &e); err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %w", caminho, err)
+	}
+	return &e, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func duracoes(valores ...int) []time.Duration {
+	d := make([]time.Duration, len(valores))
+	for i, v := range valores {
+		d[i] = time.Duration(v) * time.Millisecond
+	}
+	return d
+}
+
+func TestMannWhitney(t *testing.T) {
+	sequencia := func(inicio, n int) []time.Duration {
+		var v []int
+		for i := 0; i < n; i++ {
+			v = append(v, inicio+i)
+		}
+		return duracoes(v...)
+	}
+
+	casos := []struct {
+		nome string
+		a, b []time.Duration
+		p    float64
+	}{
+		// Exemplo da documentação do SciPy (mannwhitneyu, método
+		// assintótico com correção de continuidade): U = 17
+		{"exemplo clássico", duracoes(19, 22, 16, 29, 24), duracoes(20, 11, 17, 12), 0.11134688653314041},
+		{"simétrico", duracoes(20, 11, 17, 12), duracoes(19, 22, 16, 29, 24), 0.11134688653314041},
+		// U = 0: z = (50 - 0,5) / sqrt(175)
+		{"sem sobreposição", sequencia(1, 10), sequencia(101, 10), math.Erfc(49.5 / math.Sqrt(175) / math.Sqrt2)},
+		{"todos empatados", duracoes(5, 5, 5), duracoes(5, 5), 1},
+		{"amostra vazia", nil, duracoes(1, 2, 3), 1},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if p := mannWhitney(c.a, c.b); math.Abs(p-c.p) > 1e-9 {
+				t.Errorf("p = %v, esperado %v", p, c.p)
+			}
+		})
+	}
+}
+
+func TestCompararExecucoes(t *testing.T) {
+	cfg := &BenchConfig{Alfa: 0.05, LimiteRegressao: 10}
+	consulta := Consulta{1, "Total de vendas por ano"}
+	execucao := func(amostras []time.Duration) *ExecucaoBench {
+		return &ExecucaoBench{Resultados: []ResultadoConsulta{{
+			Consulta:     consulta,
+			Banco:        "MongoDB",
+			Amostras:     amostras,
+			Estatisticas: calcularEstatisticas(amostras),
+		}}}
+	}
+	rapidas := duracoes(10, 11, 12, 10, 11, 12, 10, 11, 12, 10)
+	lentas := duracoes(20, 21, 22, 20, 21, 22, 20, 21, 22, 20)
+
+	casos := []struct {
+		nome         string
+		antiga, nova []time.Duration
+		situacao     string
+	}{
+		{"regressão", rapidas, lentas, situacaoRegressao},
+		{"melhora", lentas, rapidas, situacaoMelhora},
+		{"estável", rapidas, rapidas, situacaoEstavel},
+		{"mediana antiga zerada", make([]time.Duration, 10), lentas, situacaoSemBase},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			comparacoes := compararExecucoes(execucao(c.antiga), execucao(c.nova), cfg)
+			if len(comparacoes) != 1 {
+				t.Fatalf("%d comparações, esperada 1", len(comparacoes))
+			}
+			got := comparacoes[0]
+			if got.Situacao != c.situacao {
+				t.Errorf("situação = %q, esperada %q", got.Situacao, c.situacao)
+			}
+			if math.IsInf(got.Variacao, 0) || math.IsNaN(got.Variacao) {
+				t.Errorf("variação = %v", got.Variacao)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Módulos cujas versões entram nos metadados de cada execução.
+var modulosDrivers = map[string]string{
+	"go.mongodb.org/mongo-driver": "MongoDB",
+	"github.com/gocql/gocql":      "Cassandra",
+	"github.com/jackc/pgx/v5":     "PostgreSQL",
+}
+
+// ExecucaoBench é o arquivo de resultados de um bench: as medições e o
+// contexto necessário para comparar execuções feitas em momentos, versões
+// e máquinas diferentes.
+//
+// O conjunto de dados vem dos metadados gravados nos bancos medidos, e não
+// da configuração do bench.
+type ExecucaoBench struct {
+	Inicio time.Time `json:"inicio"`
+	ConjuntoDados
+	Aquecimento int               `json:"aquecimento"`
+	Repeticoes  int               `json:"repeticoes"`
+	Drivers     map[string]string `json:"drivers"`
+	Servidores  map[string]string `json:"servidores"`
+	Host        Host              `json:"host"`
+
+	Resultados []ResultadoConsulta `json:"resultados"`
+
+	temConjunto bool
+}
+
+// Host descreve a máquina que executou o bench.
+type Host struct {
+	SO          string `json:"so"`
+	Arquitetura string `json:"arquitetura"`
+	CPU         string `json:"cpu"`
+	Nucleos     int    `json:"nucleos"`
+	MemoriaMB   int    `json:"memoria_mb"`
+	VersaoGo    string `json:"versao_go"`
+}
+
+// novaExecucaoBench preenche os metadados que não dependem dos bancos.
+func novaExecucaoBench(cfg *Config) *ExecucaoBench {
+	return &ExecucaoBench{
+		Inicio:      time.Now(),
+		Aquecimento: cfg.Bench.Aquecimento,
+		Repeticoes:  cfg.Bench.Repeticoes,
+		Drivers:     versoesDrivers(),
+		Servidores:  make(map[string]string),
+		Host:        lerHost(),
+	}
+}
+
+// versoesDrivers lê as versões dos drivers embutidas no binário.
+func versoesDrivers() map[string]string {
+	versoes := make(map[string]string)
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return versoes
+	}
+	for _, dep := range info.Deps {
+		if banco, ok := modulosDrivers[dep.Path]; ok {
+			versoes[banco] = dep.Path + " " + dep.Version
+		}
+	}
+	return versoes
+}
+
+// lerHost descreve a máquina local. CPU e memória vêm de /proc e ficam
+// vazias em sistemas que não o têm.
+func lerHost() Host {
+	host := Host{
+		SO:          runtime.GOOS,
+		Arquitetura: runtime.GOARCH,
+		Nucleos:     runtime.NumCPU(),
+		VersaoGo:    runtime.Version(),
+	}
+	host.CPU = campoProc("/proc/cpuinfo", "model name")
+	if memoria := campoProc("/proc/meminfo", "MemTotal"); memoria != "" {
+		kb, _ := strconv.Atoi(strings.TrimSuffix(memoria, " kB"))
+		host.MemoriaMB = kb / 1024
+	}
+	return host
+}
+
+// campoProc devolve o valor da primeira linha "chave: valor" de um arquivo
+// do /proc.
+func campoProc(caminho, chave string) string {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		nome, valor, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(nome) == chave {
+			return strings.TrimSpace(valor)
+		}
+	}
+	return ""
+}
+
+// registrarServidor guarda a versão do servidor de um executor. Uma falha
+// não invalida o bench: a versão fica registrada como desconhecida.
+func (e *ExecucaoBench) registrarServidor(ctx context.Context, executor Executor) {
+	versao, err := executor.Versao(ctx)
+	if err != nil {
+		versao = "desconhecida"
+	}
+	e.Servidores[executor.Nome()] = versao
+}
+
+// registrarConjunto guarda o conjunto de dados lido de um banco. Todos os
+// bancos de uma execução precisam ter o mesmo, senão as latências não são
+// comparáveis.
+func (e *ExecucaoBench) registrarConjunto(banco string, metadados Metadados) error {
+	conjunto, err := metadados.conjunto()
+	if err != nil {
+		return fmt.Errorf("metadados do %s: %w", banco, err)
+	}
+	if e.temConjunto && conjunto != e.ConjuntoDados {
+		return fmt.Errorf("o %s tem outro conjunto de dados (semente %d, escala %g) que os bancos anteriores (semente %d, escala %g)",
+			banco, conjunto.Semente, conjunto.Escala, e.Semente, e.Escala)
+	}
+	e.ConjuntoDados = conjunto
+	e.temConjunto = true
+	return nil
+}
+
+// salvar grava a execução em JSON e em CSV no diretório indicado, com o
+// horário de início no nome dos arquivos. Devolve os caminhos gravados.
+func (e *ExecucaoBench) salvar(diretorio string) ([]string, error) {
+	if err := os.MkdirAll(diretorio, 0o755); err != nil {
+		return nil, err
+	}
+	base := filepath.Join(diretorio, "bench-"+e.Inicio.Format("20060102-150405"))
+
+	arquivos := []string{base + ".json", base + ".csv"}
+	if err := e.salvarJSON(arquivos[0]); err != nil {
+		return nil, err
+	}
+	if err := e.salvarCSV(arquivos[1]); err != nil {
+		return nil, err
+	}
+	return arquivos, nil
+}
+
+func (e *ExecucaoBench) salvarJSON(caminho string) error {
+	dados, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(caminho, append(dados, '\n'), 0o644)
+}
+
+// salvarCSV grava uma linha por amostra, repetindo a identificação da
+// execução para que arquivos de várias execuções possam ser concatenados.
+func (e *ExecucaoBench) salvarCSV(caminho string) error {
+	f, err := os.Create(caminho)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"inicio", "escala", "semente", "consulta", "descricao", "banco", "repeticao", "latencia_ms", "linhas"})
+	for _, r := range e.Resultados {
+		for i, amostra := range r.Amostras {
+			w.Write([]string{
+				e.Inicio.Format(time.RFC3339),
+				strconv.FormatFloat(e.Escala, 'g', -1, 64),
+				strconv.FormatInt(e.Semente, 10),
+				strconv.Itoa(r.Consulta.Numero),
+				r.Consulta.Descricao,
+				r.Banco,
+				strconv.Itoa(i + 1),
+				strconv.FormatFloat(float64(amostra)/float64(time.Millisecond), 'f', 3, 64),
+				strconv.Itoa(r.Linhas),
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
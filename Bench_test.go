@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalcularEstatisticas(t *testing.T) {
+	var cem []int
+	for i := 100; i >= 1; i-- {
+		cem = append(cem, i)
+	}
+
+	casos := []struct {
+		nome     string
+		amostras []time.Duration
+		esperado Estatisticas
+	}{
+		{"sem amostras", nil, Estatisticas{}},
+		{"uma amostra", duracoes(7), Estatisticas{
+			Min: 7 * time.Millisecond, Mediana: 7 * time.Millisecond, P95: 7 * time.Millisecond,
+			P99: 7 * time.Millisecond, Max: 7 * time.Millisecond,
+		}},
+		// Posto mais próximo: mediana no posto 3 e p95/p99 no posto 5
+		{"cinco amostras fora de ordem", duracoes(5, 1, 4, 2, 3), Estatisticas{
+			Min: 1 * time.Millisecond, Mediana: 3 * time.Millisecond, P95: 5 * time.Millisecond,
+			P99: 5 * time.Millisecond, Max: 5 * time.Millisecond,
+		}},
+		{"cem amostras", duracoes(cem...), Estatisticas{
+			Min: 1 * time.Millisecond, Mediana: 50 * time.Millisecond, P95: 95 * time.Millisecond,
+			P99: 99 * time.Millisecond, Max: 100 * time.Millisecond,
+		}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if got := calcularEstatisticas(c.amostras); got != c.esperado {
+				t.Errorf("calcularEstatisticas = %+v, esperado %+v", got, c.esperado)
+			}
+		})
+	}
+}
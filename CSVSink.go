@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// formatoDataHoraCSV é o formato das datas no CSV (ISO 8601), aceito pelo
+// cqlsh COPY e pelo DSBulk.
+const formatoDataHoraCSV = "2006-01-02T15:04:05Z07:00"
+
+// CSVSink grava cada tabela em <tabela>.csv dentro de Arquivos.Diretorio,
+// com uma linha de cabeçalho com os nomes das colunas. Campos omitempty
+// zerados (como cod_promocao sem promoção) ficam vazios, o que o cqlsh
+// grava como null e o mongoimport --ignoreBlanks omite.
+type CSVSink struct {
+	cfg *Config
+
+	mu       sync.Mutex
+	arquivos map[string]*arquivoCSV
+}
+
+// arquivoCSV é o arquivo de uma tabela. Escrever é chamado concorrentemente,
+// então cada arquivo tem sua própria trava.
+type arquivoCSV struct {
+	mu      sync.Mutex
+	arquivo *arquivoSaida
+	w       *csv.Writer
+}
+
+func novoCSVSink(cfg *Config) *CSVSink {
+	return &CSVSink{cfg: cfg, arquivos: make(map[string]*arquivoCSV)}
+}
+
+func (s *CSVSink) Nome() string { return "CSV" }
+
+func (s *CSVSink) Abrir(ctx context.Context) error { return nil }
+
+func (s *CSVSink) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	a, err := s.arquivo(tabela, registros[0])
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, r := range registros {
+		if err := a.w.Write(s.linha(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arquivo devolve o arquivo da tabela, criando-o com o cabeçalho no
+// primeiro uso.
+func (s *CSVSink) arquivo(tabela string, modelo Registro) (*arquivoCSV, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.arquivos[tabela]; ok {
+		return a, nil
+	}
+
+	arquivo, err := criarArquivo(s.cfg, tabela+".csv")
+	if err != nil {
+		return nil, err
+	}
+	a := &arquivoCSV{arquivo: arquivo, w: csv.NewWriter(arquivo)}
+	a.w.Comma = []rune(s.cfg.Arquivos.CSV.Delimitador)[0]
+	if err := a.w.Write(colunasDe(modelo)); err != nil {
+		arquivo.Close()
+		return nil, err
+	}
+
+	s.arquivos[tabela] = a
+	return a, nil
+}
+
+// linha formata os valores de um registro na ordem das colunas.
+func (s *CSVSink) linha(r Registro) []string {
+	v := reflect.ValueOf(r)
+	campos := camposDe(v.Type())
+	linha := make([]string, len(campos))
+	for i, c := range campos {
+		campo := v.Field(c.indice)
+		if c.opcional && campo.IsZero() {
+			continue
+		}
+		linha[i] = s.formatar(campo.Interface())
+	}
+	return linha
+}
+
+func (s *CSVSink) formatar(valor any) string {
+	switch v := valor.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strings.Replace(strconv.FormatFloat(v, 'f', -1, 64), ".", s.cfg.Arquivos.CSV.Decimal, 1)
+	case time.Time:
+		return v.Format(formatoDataHoraCSV)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (s *CSVSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var erros []error
+	for _, a := range s.arquivos {
+		a.mu.Lock()
+		a.w.Flush()
+		erros = append(erros, a.w.Error())
+		a.mu.Unlock()
+	}
+	return errors.Join(erros...)
+}
+
+func (s *CSVSink) Fechar(ctx context.Context) error {
+	err := s.Flush(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	erros := []error{err}
+	for tabela, a := range s.arquivos {
+		erros = append(erros, a.arquivo.Close())
+		delete(s.arquivos, tabela)
+	}
+	return errors.Join(erros...)
+}
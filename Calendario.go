@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Datas dos picos de movimento, além do Dia das Mães (segundo domingo de
+// maio) e da Black Friday (sexta-feira depois da quarta quinta de novembro),
+// que mudam a cada ano.
+const (
+	inicioPicoNatal = 15 // de 15 a 24 de dezembro
+	fimPicoNatal    = 24
+	diasPicoMaes    = 7 // a semana que antecede o Dia das Mães
+)
+
+// fusoLojas é o horário das lojas, em que valem a curva de horas e as
+// datas do calendário: o de Brasília, sem horário de verão desde 2019. Os
+// bancos guardam instantes em UTC; as consultas por ano e a data do
+// Parquet convertem de volta para este fuso.
+const horasFusoLojas = -3
+
+var fusoLojas = time.FixedZone("BRT", horasFusoLojas*60*60)
+
+// inicioDiaLoja devolve, em UTC, o instante em que começa a data civil dia
+// no relógio das lojas. As vigências começam e terminam nesses instantes,
+// para valer o dia inteiro da loja.
+func inicioDiaLoja(dia time.Time) time.Time {
+	return time.Date(dia.Year(), dia.Month(), dia.Day(), 0, 0, 0, 0, fusoLojas).UTC()
+}
+
+// calendarioVendas sorteia dat_nota: o dia segundo o peso do dia da semana,
+// o crescimento anual e os picos do varejo, e a hora segundo a curva de
+// movimento do dia. Os pesos são calculados uma vez para todo o período,
+// então o calendário pode ser compartilhado pelas goroutines geradoras.
+type calendarioVendas struct {
+	inicio time.Time // data civil, sem fuso (meia-noite UTC)
+	dias   *sorteador
+	horas  *sorteador
+}
+
+func novoCalendarioVendas(cfg *Config) *calendarioVendas {
+	t := cfg.Temporal
+	inicio, fim := cfg.periodoNotas()
+	numDias := int(fim.Sub(inicio)/(24*time.Hour)) + 1
+
+	pesos := make([]float64, numDias)
+	for i := range pesos {
+		dia := inicio.AddDate(0, 0, i)
+		anos := float64(i) / 365.25
+		pesos[i] = t.PesosDiasSemana[dia.Weekday()] *
+			math.Pow(1+t.CrescimentoAnual/100, anos) *
+			fatorPico(t.Picos, dia)
+	}
+
+	return &calendarioVendas{
+		inicio: inicio,
+		dias:   novoSorteador(numDias, pesos),
+		horas:  novoSorteador(len(t.PesosHoras), t.PesosHoras),
+	}
+}
+
+// sortear devolve o momento de uma venda, com hora, minuto e segundo. O
+// dia sorteado e a hora da curva são os do relógio da loja (fusoLojas).
+func (c *calendarioVendas) sortear(rng *rand.Rand) time.Time {
+	dia := c.inicio.AddDate(0, 0, c.dias.sortear(rng))
+	hora := c.horas.sortear(rng)
+	segundos := rng.Intn(3600)
+	return time.Date(dia.Year(), dia.Month(), dia.Day(), hora, segundos/60, segundos%60, 0, fusoLojas)
+}
+
+// fatorPico devolve o multiplicador de movimento de um dia (uma data civil,
+// à meia-noite UTC): Black Friday (sexta e sábado), Natal e a semana do Dia
+// das Mães. Fora deles, 1.
+func fatorPico(p PicosConfig, dia time.Time) float64 {
+	ano := dia.Year()
+
+	blackFriday := enesimoDiaSemana(ano, time.November, time.Thursday, 4).AddDate(0, 0, 1)
+	if !dia.Before(blackFriday) && dia.Before(blackFriday.AddDate(0, 0, 2)) {
+		return p.BlackFriday
+	}
+
+	if dia.Month() == time.December && dia.Day() >= inicioPicoNatal && dia.Day() <= fimPicoNatal {
+		return p.Natal
+	}
+
+	diaDasMaes := enesimoDiaSemana(ano, time.May, time.Sunday, 2)
+	if dia.Before(diaDasMaes) && !dia.Before(diaDasMaes.AddDate(0, 0, -diasPicoMaes)) {
+		return p.DiaDasMaes
+	}
+	return 1
+}
+
+// enesimoDiaSemana devolve a n-ésima ocorrência do dia da semana no mês.
+func enesimoDiaSemana(ano int, mes time.Month, diaSemana time.Weekday, n int) time.Time {
+	primeiro := time.Date(ano, mes, 1, 0, 0, 0, 0, time.UTC)
+	desloc := (int(diaSemana) - int(primeiro.Weekday()) + 7) % 7
+	return primeiro.AddDate(0, 0, desloc+7*(n-1))
+}
+
+// validar confere o período e as curvas do modelo temporal. dataReferencia
+// é o fim do período quando Fim não é informado.
+func (t TemporalConfig) validar(dataReferencia string) []error {
+	var erros []error
+
+	datasValidas := true
+	for nome, data := range map[string]string{"temporal.inicio": t.Inicio, "temporal.fim": t.Fim} {
+		if data == "" {
+			continue
+		}
+		if _, err := time.Parse(formatoData, data); err != nil {
+			erros = append(erros, fmt.Errorf("%s inválido: %w", nome, err))
+			datasValidas = false
+		}
+	}
+	if datasValidas {
+		cfg := Config{DataReferencia: dataReferencia, Temporal: t}
+		if inicio, fim := cfg.periodoNotas(); inicio.After(fim) {
+			erros = append(erros, fmt.Errorf("temporal.inicio (%s) é posterior ao fim (%s)",
+				inicio.Format(formatoData), fim.Format(formatoData)))
+		}
+	}
+
+	erros = append(erros, validarPesos("temporal.pesos_dias_semana", t.PesosDiasSemana, 7)...)
+	erros = append(erros, validarPesos("temporal.pesos_horas", t.PesosHoras, 24)...)
+
+	if t.CrescimentoAnual <= -100 {
+		erros = append(erros, fmt.Errorf("temporal.crescimento_anual deve ser maior que -100 (atual: %g)", t.CrescimentoAnual))
+	}
+	for nome, fator := range map[string]float64{
+		"temporal.picos.black_friday": t.Picos.BlackFriday,
+		"temporal.picos.natal":        t.Picos.Natal,
+		"temporal.picos.dia_das_maes": t.Picos.DiaDasMaes,
+	} {
+		if fator <= 0 {
+			erros = append(erros, fmt.Errorf("%s deve ser maior que zero (atual: %g)", nome, fator))
+		}
+	}
+	return erros
+}
+
+// validarPesos exige n pesos não negativos, com pelo menos um positivo.
+func validarPesos(nome string, pesos []float64, n int) []error {
+	if len(pesos) != n {
+		return []error{fmt.Errorf("%s deve ter %d pesos (atual: %d)", nome, n, len(pesos))}
+	}
+	total := 0.0
+	for i, p := range pesos {
+		if p < 0 {
+			return []error{fmt.Errorf("%s[%d] não pode ser negativo (atual: %g)", nome, i, p)}
+		}
+		total += p
+	}
+	if total == 0 {
+		return []error{fmt.Errorf("%s precisa de pelo menos um peso positivo", nome)}
+	}
+	return nil
+}
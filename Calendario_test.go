@@ -0,0 +1,140 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func dataFixa(s string) time.Time {
+	d, err := time.Parse(formatoData, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestEnesimoDiaSemana(t *testing.T) {
+	casos := []struct {
+		nome      string
+		ano       int
+		mes       time.Month
+		diaSemana time.Weekday
+		n         int
+		esperada  string
+	}{
+		// Quarta quinta-feira de novembro: a véspera da Black Friday
+		{"Ação de Graças 2021", 2021, time.November, time.Thursday, 4, "2021-11-25"},
+		{"Ação de Graças 2022", 2022, time.November, time.Thursday, 4, "2022-11-24"},
+		{"Ação de Graças 2023", 2023, time.November, time.Thursday, 4, "2023-11-23"},
+		{"Ação de Graças 2024", 2024, time.November, time.Thursday, 4, "2024-11-28"},
+		{"Ação de Graças 2025", 2025, time.November, time.Thursday, 4, "2025-11-27"},
+		// Segundo domingo de maio
+		{"Dia das Mães 2024", 2024, time.May, time.Sunday, 2, "2024-05-12"},
+		{"Dia das Mães 2025", 2025, time.May, time.Sunday, 2, "2025-05-11"},
+		// O mês começa no próprio dia da semana procurado
+		{"primeira sexta de novembro de 2024", 2024, time.November, time.Friday, 1, "2024-11-01"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if got := enesimoDiaSemana(c.ano, c.mes, c.diaSemana, c.n); !got.Equal(dataFixa(c.esperada)) {
+				t.Errorf("enesimoDiaSemana = %s, esperado %s", got.Format(formatoData), c.esperada)
+			}
+		})
+	}
+}
+
+func TestFatorPico(t *testing.T) {
+	picos := PicosConfig{BlackFriday: 5, Natal: 3, DiaDasMaes: 2}
+
+	casos := []struct {
+		dia      string
+		esperado float64
+	}{
+		{"2022-11-25", 5}, // Black Friday
+		{"2023-11-24", 5},
+		{"2024-11-29", 5},
+		{"2024-11-30", 5}, // sábado seguinte
+		{"2025-11-28", 5},
+		{"2024-11-28", 1}, // quinta de Ação de Graças
+		{"2024-12-01", 1}, // domingo depois da Black Friday
+		{"2024-12-14", 1},
+		{"2024-12-15", 3}, // Natal
+		{"2024-12-24", 3},
+		{"2024-12-25", 1},
+		{"2024-05-04", 1},
+		{"2024-05-05", 2}, // semana do Dia das Mães (12/05)
+		{"2024-05-11", 2},
+		{"2024-05-12", 1},
+	}
+	for _, c := range casos {
+		t.Run(c.dia, func(t *testing.T) {
+			if got := fatorPico(picos, dataFixa(c.dia)); got != c.esperado {
+				t.Errorf("fatorPico = %g, esperado %g", got, c.esperado)
+			}
+		})
+	}
+}
+
+// A hora sorteada é a do relógio da loja, não a de UTC.
+func TestCalendarioHorarioDasLojas(t *testing.T) {
+	pesosDias := []float64{1, 1, 1, 1, 1, 1, 1}
+	casos := []struct {
+		nome string
+		hora int
+	}{
+		{"manhã", 10},
+		{"noite, já no dia seguinte em UTC", 22},
+		{"madrugada", 0},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			pesosHoras := make([]float64, 24)
+			pesosHoras[c.hora] = 1
+			cfg := &Config{Temporal: TemporalConfig{
+				Inicio:          "2024-12-31",
+				Fim:             "2024-12-31",
+				PesosDiasSemana: pesosDias,
+				PesosHoras:      pesosHoras,
+				Picos:           PicosConfig{BlackFriday: 1, Natal: 1, DiaDasMaes: 1},
+			}}
+			calendario := novoCalendarioVendas(cfg)
+
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 100; i++ {
+				local := calendario.sortear(rng).In(fusoLojas)
+				if local.Hour() != c.hora || local.Format(formatoData) != "2024-12-31" {
+					t.Fatalf("sorteado %v, esperado 31/12/2024 às %dh no horário das lojas", local, c.hora)
+				}
+			}
+		})
+	}
+}
+
+// Uma nota das 23h do último dia, já no dia seguinte em UTC, cai na
+// vigência do PDV que a emitiu, inclusive quando a vigência foi estendida
+// até o fim do período.
+func TestVigenciaPDVCobreUltimaNoite(t *testing.T) {
+	pesosHoras := make([]float64, 24)
+	pesosHoras[23] = 1
+	cfg := &Config{Temporal: TemporalConfig{
+		Inicio:          "2024-12-31",
+		Fim:             "2024-12-31",
+		PesosDiasSemana: []float64{1, 1, 1, 1, 1, 1, 1},
+		PesosHoras:      pesosHoras,
+		Picos:           PicosConfig{BlackFriday: 1, Natal: 1, DiaDasMaes: 1},
+	}}
+	calendario := novoCalendarioVendas(cfg)
+
+	// Dez anos de notas: os 5 anos de vigência nunca bastam
+	inicioNotas, fimNotas := dataFixa("2015-01-01"), dataFixa("2024-12-31")
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		inicio, fim := vigenciaPDV(rng, inicioNotas, fimNotas)
+		nota := calendario.sortear(rng)
+		if nota.Before(inicio) || !nota.Before(fim) {
+			t.Fatalf("nota de %v fora da vigência [%v, %v)", nota.UTC(), inicio, fim)
+		}
+	}
+}
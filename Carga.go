@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// executarCarga trata "load": gera o conjunto de dados uma única vez, em
+// memória, e depois o grava em cada banco de --sinks, um de cada vez, para
+// medir a velocidade de escrita de cada um isoladamente.
+func executarCarga(args []string) {
+	cfg, ok := configurar("load", args)
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+
+	fmt.Printf("Semente: %d (use --seed %d para reproduzir esta execução)\n", cfg.Semente, cfg.Semente)
+	fmt.Println("Gerando dados em memória...")
+	memoria := novoMemoriaSink()
+	gerarDados(ctx, &cfg, novaSaida(&cfg, []Sink{memoria}))
+	fmt.Printf("Gerados %d registros\n", memoria.total())
+
+	for _, nome := range cfg.Sinks {
+		// Cada banco é carregado com a configuração de um único sink
+		cfgBanco := cfg
+		cfgBanco.Sinks = []string{nome}
+
+		if cfg.Carga.RecriarSchema {
+			for _, esquema := range criarEsquemas(&cfgBanco) {
+				if err := esquema.Remover(ctx); err != nil {
+					log.Fatalf("Erro ao remover schema no %s: %v", esquema.Nome(), err)
+				}
+				if err := esquema.Criar(ctx); err != nil {
+					log.Fatalf("Erro ao criar schema no %s: %v", esquema.Nome(), err)
+				}
+			}
+		}
+
+		sinks, err := criarSinks(&cfgBanco)
+		if err != nil {
+			log.Fatalf("Erro na configuração dos sinks: %v", err)
+		}
+		medido := novoSinkMedido(sinks[0])
+		if err := medido.Abrir(ctx); err != nil {
+			log.Fatalf("Erro ao abrir %s: %v", medido.Nome(), err)
+		}
+
+		// Tabelas que o sink descartaria não entram na medição
+		var tabelas []string
+		for _, tabela := range memoria.tabelas() {
+			seletivo, ok := sinks[0].(SinkSeletivo)
+			if !ok || seletivo.Aceita(memoria.registros[tabela][0]) {
+				tabelas = append(tabelas, tabela)
+			}
+		}
+
+		fmt.Printf("\nCarregando %s...\n", medido.Nome())
+		inicio := time.Now()
+		for _, tabela := range tabelas {
+			carregarTabela(ctx, &cfgBanco, medido, tabela, memoria.registros[tabela])
+		}
+		if err := medido.Flush(ctx); err != nil {
+			log.Printf("Erro ao descarregar %s: %v", medido.Nome(), err)
+		}
+		total := time.Since(inicio)
+
+		if err := medido.Fechar(ctx); err != nil {
+			log.Printf("Erro ao fechar %s: %v", medido.Nome(), err)
+		}
+		medido.imprimir(tabelas, total)
+
+		// Fora da medição da carga, como na geração
+		criarIndicesAdiados(ctx, &cfgBanco)
+	}
+}
+
+// carregarTabela grava os registros de uma tabela em lotes do tamanho usado
+// na geração, distribuídos entre as goroutines configuradas. Só retorna
+// depois de toda a tabela gravada, para que as tabelas referenciadas
+// terminem antes das que as referenciam.
+func carregarTabela(ctx context.Context, cfg *Config, sink *SinkMedido, tabela string, registros []Registro) {
+	tamanhoLote := novaSaida(cfg, nil).tamanhoLote
+	lotes := make(chan []Registro)
+
+	inicio := time.Now()
+	var wg sync.WaitGroup
+	for g := 0; g < cfg.NumGoroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lote := range lotes {
+				sink.Escrever(ctx, tabela, lote)
+			}
+		}()
+	}
+
+	for i := 0; i < len(registros); i += tamanhoLote {
+		lotes <- registros[i:min(i+tamanhoLote, len(registros))]
+	}
+	close(lotes)
+	wg.Wait()
+
+	sink.medicao(tabela).duracao = time.Since(inicio)
+}
+
+// limitesHistograma são os limites superiores das faixas de latência dos
+// lotes; a última faixa reúne o que passar do último limite.
+var limitesHistograma = []time.Duration{
+	time.Millisecond, 2 * time.Millisecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 200 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2 * time.Second, 5 * time.Second,
+}
+
+// medicaoTabela acumula o que foi observado nas escritas de uma tabela.
+type medicaoTabela struct {
+	linhas      int
+	lotes       int
+	erros       int
+	primeiroErr error
+	histograma  []int // uma posição por faixa de limitesHistograma, mais a excedente
+	duracao     time.Duration
+}
+
+// SinkMedido envolve um sink e mede cada chamada a Escrever: latência do
+// lote, linhas gravadas e registros com erro, por tabela.
+type SinkMedido struct {
+	Sink
+
+	mu       sync.Mutex
+	medicoes map[string]*medicaoTabela
+}
+
+func novoSinkMedido(sink Sink) *SinkMedido {
+	return &SinkMedido{Sink: sink, medicoes: make(map[string]*medicaoTabela)}
+}
+
+func (s *SinkMedido) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	inicio := time.Now()
+	err := s.Sink.Escrever(ctx, tabela, registros)
+	latencia := time.Since(inicio)
+
+	erros := 0
+	if err != nil {
+		var erroLote *ErroLote
+		if errors.As(err, &erroLote) {
+			erros = len(erroLote.Falhas)
+		} else {
+			erros = len(registros)
+		}
+	}
+
+	faixa := len(limitesHistograma)
+	for i, limite := range limitesHistograma {
+		if latencia <= limite {
+			faixa = i
+			break
+		}
+	}
+
+	m := s.medicao(tabela)
+	s.mu.Lock()
+	m.linhas += len(registros) - erros
+	m.lotes++
+	m.erros += erros
+	if err != nil && m.primeiroErr == nil {
+		m.primeiroErr = err
+	}
+	m.histograma[faixa]++
+	s.mu.Unlock()
+	return err
+}
+
+// medicao devolve a medição de uma tabela, criando-a no primeiro uso.
+func (s *SinkMedido) medicao(tabela string) *medicaoTabela {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.medicoes[tabela]
+	if !ok {
+		m = &medicaoTabela{histograma: make([]int, len(limitesHistograma)+1)}
+		s.medicoes[tabela] = m
+	}
+	return m
+}
+
+// imprimir mostra a vazão e os erros de cada tabela e o histograma das
+// latências dos lotes.
+func (s *SinkMedido) imprimir(tabelas []string, total time.Duration) {
+	fmt.Printf("\n%-32s %10s %8s %12s %8s\n", "Tabela", "linhas", "lotes", "linhas/s", "erros")
+
+	linhas, erros := 0, 0
+	for _, tabela := range tabelas {
+		m := s.medicao(tabela)
+		linhas += m.linhas
+		erros += m.erros
+		fmt.Printf("%-32s %10d %8d %12.0f %8d\n", tabela, m.linhas, m.lotes, float64(m.linhas)/m.duracao.Seconds(), m.erros)
+	}
+	fmt.Printf("%-32s %10d %8s %12.0f %8d\n", "Total", linhas, "", float64(linhas)/total.Seconds(), erros)
+
+	fmt.Printf("\nLatência dos lotes no %s:\n", s.Nome())
+	for _, tabela := range tabelas {
+		m := s.medicao(tabela)
+		var faixas []string
+		for i, n := range m.histograma {
+			if n == 0 {
+				continue
+			}
+			rotulo := "> " + limitesHistograma[len(limitesHistograma)-1].String()
+			if i < len(limitesHistograma) {
+				rotulo = "≤ " + limitesHistograma[i].String()
+			}
+			faixas = append(faixas, fmt.Sprintf("%s: %d", rotulo, n))
+		}
+		fmt.Printf("  %-30s %s\n", tabela, strings.Join(faixas, ", "))
+		if m.primeiroErr != nil {
+			fmt.Printf("  %-30s primeiro erro: %v\n", "", m.primeiroErr)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// executorCassandra roda as consultas do README, lendo das tabelas de
+// consulta quando o modelo normalizado não as atende.
+//
+// O CQL não tem EXTRACT nem ORDER BY sobre agregados, então a consulta 1 lê
+// as notas e soma por ano no cliente, e a consulta 2 escolhe os 5 maiores
+// depois do GROUP BY. A consulta 5 usa vlr_lucro, gravado já calculado, e
+// a 6 lê a partição do CPF em cliente_por_cpf.
+type executorCassandra struct {
+	cfg     *Config
+	session *gocql.Session
+	cpf     string // procurado na consulta 6
+}
+
+func (e *executorCassandra) Nome() string { return "Cassandra" }
+
+func (e *executorCassandra) Abrir(ctx context.Context) error {
+	session, err := conectarCassandra(e.cfg, e.cfg.Cassandra.Keyspace)
+	if err != nil {
+		return err
+	}
+	e.session = session
+	return nil
+}
+
+func (e *executorCassandra) Versao(ctx context.Context) (string, error) {
+	var versao string
+	err := e.session.Query(`SELECT release_version FROM system.local`).WithContext(ctx).Scan(&versao)
+	return versao, err
+}
+
+func (e *executorCassandra) Metadados(ctx context.Context) (Metadados, error) {
+	m := Metadados{Chave: chaveMetadados}
+	err := e.session.Query(`SELECT semente, escala, data_referencia, volumes FROM metadados WHERE chave = ?`, m.Chave).
+		WithContext(ctx).Scan(&m.Semente, &m.Escala, &m.DataReferencia, &m.Volumes)
+	return m, err
+}
+
+// Preparar lê o CPF da tabela cliente; a consulta o procura em
+// cliente_por_cpf, então um cliente que falte lá aparece como busca vazia.
+func (e *executorCassandra) Preparar(ctx context.Context, c Consulta) error {
+	if c.Numero != consultaClientePorCPF {
+		return nil
+	}
+	return e.session.Query(`SELECT num_cpf FROM cliente WHERE cod_cliente = ?`, codClienteConsulta).
+		WithContext(ctx).Scan(&e.cpf)
+}
+
+func (e *executorCassandra) Executar(ctx context.Context, c Consulta) (int, error) {
+	switch c.Numero {
+	case 1:
+		return e.vendasPorAno(ctx)
+	case 2:
+		return e.produtosMaisVendidos(ctx, 5)
+	case 3:
+		return e.contarGrupos(ctx, `SELECT estado, SUM(vlr_nota) FROM nota_fiscal_por_estado GROUP BY estado`)
+	case 4:
+		return e.contarGrupos(ctx, `SELECT cidade, COUNT(*) FROM cliente_fidelizado_por_cidade GROUP BY cidade`)
+	case 5:
+		return e.contarGrupos(ctx, `SELECT setor, AVG(vlr_lucro) FROM item_nota_fiscal_por_setor GROUP BY setor`)
+	case consultaClientePorCPF:
+		return e.contarGrupos(ctx, `SELECT cod_cliente, nom_cliente FROM cliente_por_cpf WHERE num_cpf = ?`, e.cpf)
+	}
+	return 0, fmt.Errorf("consulta %d sem CQL no Cassandra", c.Numero)
+}
+
+func (e *executorCassandra) vendasPorAno(ctx context.Context) (int, error) {
+	totais := make(map[int]float64)
+	iter := e.session.Query(`SELECT dat_nota, vlr_nota FROM nota_fiscal`).WithContext(ctx).Iter()
+
+	var datNota time.Time
+	var vlrNota float64
+	for iter.Scan(&datNota, &vlrNota) {
+		totais[datNota.In(fusoLojas).Year()] += vlrNota
+	}
+	return len(totais), iter.Close()
+}
+
+func (e *executorCassandra) produtosMaisVendidos(ctx context.Context, limite int) (int, error) {
+	type produtoVendido struct {
+		nome       string
+		quantidade float64
+	}
+
+	var vendidos []produtoVendido
+	iter := e.session.Query(`
+		SELECT produto_nome, SUM(qtd_produto)
+		FROM item_nota_fiscal_por_produto
+		GROUP BY produto_nome
+	`).WithContext(ctx).Iter()
+
+	var p produtoVendido
+	for iter.Scan(&p.nome, &p.quantidade) {
+		vendidos = append(vendidos, p)
+	}
+	if err := iter.Close(); err != nil {
+		return 0, err
+	}
+
+	sort.Slice(vendidos, func(i, j int) bool { return vendidos[i].quantidade > vendidos[j].quantidade })
+	return min(limite, len(vendidos)), nil
+}
+
+// contarGrupos consome uma consulta GROUP BY e devolve quantos grupos (ou
+// linhas, sem GROUP BY) vieram.
+func (e *executorCassandra) contarGrupos(ctx context.Context, cql string, valores ...any) (int, error) {
+	iter := e.session.Query(cql, valores...).WithContext(ctx).Iter()
+	scanner := iter.Scanner()
+
+	grupos := 0
+	for scanner.Next() {
+		grupos++
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return grupos, nil
+}
+
+func (e *executorCassandra) Fechar(ctx context.Context) error {
+	if e.session != nil {
+		e.session.Close()
+	}
+	return nil
+}
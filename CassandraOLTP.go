@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+
+	"github.com/gocql/gocql"
+)
+
+// oltpCassandra executa as operações de caixa com comandos de uma linha,
+// todos preparados pelo gocql no primeiro uso.
+type oltpCassandra struct {
+	cfg     *Config
+	session *gocql.Session
+}
+
+func (o *oltpCassandra) Nome() string { return "Cassandra" }
+
+func (o *oltpCassandra) Abrir(ctx context.Context) error {
+	session, err := conectarCassandra(o.cfg, o.cfg.Cassandra.Keyspace)
+	if err != nil {
+		return err
+	}
+	o.session = session
+	return nil
+}
+
+func (o *oltpCassandra) Metadados(ctx context.Context) (Metadados, error) {
+	m := Metadados{Chave: chaveMetadados}
+	err := o.session.Query(`SELECT maior_seq_nota, maiores_num_nota FROM metadados WHERE chave = ?`, m.Chave).
+		WithContext(ctx).Scan(&m.MaiorSeqNota, &m.MaioresNumNota)
+	return m, err
+}
+
+func (o *oltpCassandra) GravarNumeracao(ctx context.Context, maiorSeqNota int, maioresNumNota string) error {
+	return o.session.Query(`UPDATE metadados SET maior_seq_nota = ?, maiores_num_nota = ? WHERE chave = ?`,
+		maiorSeqNota, maioresNumNota, chaveMetadados).WithContext(ctx).Exec()
+}
+
+func (o *oltpCassandra) inserir(ctx context.Context, r Registro) error {
+	return o.session.Query(comandoInsert(r.Tabela(), colunasDe(r)), valoresDe(r)...).WithContext(ctx).Exec()
+}
+
+func (o *oltpCassandra) AbrirNota(ctx context.Context, nota NotaFiscal) error {
+	return o.inserir(ctx, nota)
+}
+
+func (o *oltpCassandra) LerProduto(ctx context.Context, codProduto int) (Produto, error) {
+	produto := Produto{CodProduto: codProduto}
+	err := o.session.Query(`
+		SELECT vlr_venda, vlr_custo, vlr_medio, vlr_promocao
+		FROM produto WHERE cod_produto = ?
+	`, codProduto).WithContext(ctx).Scan(&produto.VlrVenda, &produto.VlrCusto, &produto.VlrMedio, &produto.VlrPromocao)
+	return produto, err
+}
+
+func (o *oltpCassandra) AdicionarItem(ctx context.Context, item ItemNotaFiscal) error {
+	return o.inserir(ctx, item)
+}
+
+func (o *oltpCassandra) BuscarCliente(ctx context.Context, codCliente int) (Cliente, error) {
+	cliente := Cliente{CodCliente: codCliente}
+	err := o.session.Query(`
+		SELECT nom_cliente, flg_fidelizado, cod_endereco
+		FROM cliente WHERE cod_cliente = ?
+	`, codCliente).WithContext(ctx).Scan(&cliente.NomCliente, &cliente.FlgFidelizado, &cliente.CodEndereco)
+	return cliente, err
+}
+
+// FecharNota grava o total da nota, pago em cartão.
+func (o *oltpCassandra) FecharNota(ctx context.Context, seqNota int, vlrNota float64) error {
+	return o.session.Query(`UPDATE nota_fiscal SET vlr_nota = ?, vlr_cartao = ? WHERE seq_nota = ?`,
+		vlrNota, vlrNota, seqNota).WithContext(ctx).Exec()
+}
+
+func (o *oltpCassandra) Fechar(ctx context.Context) error {
+	if o.session != nil {
+		o.session.Close()
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Estratégias de replicação aceitas para o keyspace
+const (
+	replicacaoSimples   = "SimpleStrategy"
+	replicacaoTopologia = "NetworkTopologyStrategy"
+)
+
+// Valores de system_schema.columns.kind
+const (
+	tipoColunaParticao   = "partition_key"
+	tipoColunaClustering = "clustering"
+	tipoColunaRegular    = "regular"
+)
+
+// colunaCQL é uma coluna esperada de uma tabela do Cassandra.
+type colunaCQL struct {
+	nome string
+	tipo string
+	kind string
+}
+
+// tabelaCQL é a definição esperada de uma tabela, derivada de uma struct.
+type tabelaCQL struct {
+	nome       string
+	colunas    []colunaCQL
+	particao   []string
+	clustering []string
+}
+
+// tabelaCQLDe deriva a tabela das tags bson e chave do registro modelo.
+func tabelaCQLDe(r Registro) tabelaCQL {
+	t := reflect.TypeOf(r)
+	tabela := tabelaCQL{nome: r.Tabela()}
+
+	for _, c := range camposDe(t) {
+		coluna := colunaCQL{
+			nome: c.coluna,
+			tipo: tipoCQL(t.Field(c.indice).Type),
+			kind: tipoColunaRegular,
+		}
+		switch c.chave {
+		case chaveParticao:
+			coluna.kind = tipoColunaParticao
+			tabela.particao = append(tabela.particao, c.coluna)
+		case chaveClustering:
+			coluna.kind = tipoColunaClustering
+			tabela.clustering = append(tabela.clustering, c.coluna)
+		}
+		tabela.colunas = append(tabela.colunas, coluna)
+	}
+	return tabela
+}
+
+// tipoCQL converte o tipo Go de um campo no tipo CQL da coluna.
+func tipoCQL(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "timestamp"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int32:
+		return "int"
+	case reflect.Int64:
+		return "bigint"
+	case reflect.Float64:
+		return "double"
+	case reflect.String:
+		return "text"
+	case reflect.Bool:
+		return "boolean"
+	}
+	panic(fmt.Sprintf("tipo sem equivalente CQL: %s", t))
+}
+
+// ddl monta o CREATE TABLE da tabela no keyspace.
+func (t tabelaCQL) ddl(keyspace string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s.%s (\n", keyspace, t.nome)
+	for _, c := range t.colunas {
+		fmt.Fprintf(&b, "  %s %s,\n", c.nome, c.tipo)
+	}
+
+	chave := "(" + strings.Join(t.particao, ", ") + ")"
+	if len(t.clustering) > 0 {
+		chave += ", " + strings.Join(t.clustering, ", ")
+	}
+	fmt.Fprintf(&b, "  PRIMARY KEY (%s)\n)", chave)
+	return b.String()
+}
+
+// ddlKeyspace monta o CREATE KEYSPACE com a replicação configurada.
+func ddlKeyspace(cfg *Config) string {
+	r := cfg.Cassandra.Replicacao
+	opcoes := fmt.Sprintf("'class': '%s'", r.Estrategia)
+	if r.Estrategia == replicacaoSimples {
+		opcoes += fmt.Sprintf(", 'replication_factor': %d", r.Fator)
+	} else {
+		dcs := make([]string, 0, len(r.DataCenters))
+		for dc := range r.DataCenters {
+			dcs = append(dcs, dc)
+		}
+		sort.Strings(dcs)
+		for _, dc := range dcs {
+			opcoes += fmt.Sprintf(", '%s': %d", dc, r.DataCenters[dc])
+		}
+	}
+	return fmt.Sprintf("CREATE KEYSPACE IF NOT EXISTS %s WITH replication = {%s}", cfg.Cassandra.Keyspace, opcoes)
+}
+
+// tabelasCassandra são as tabelas normalizadas seguidas das de consulta.
+func tabelasCassandra() []Registro {
+	return slices.Concat(tabelas, tabelasConsulta)
+}
+
+// esquemaCassandra cria o keyspace e as tabelas a partir das structs.
+type esquemaCassandra struct {
+	cfg *Config
+}
+
+func (e *esquemaCassandra) Nome() string { return "Cassandra" }
+
+func (e *esquemaCassandra) Criar(ctx context.Context) error {
+	session, err := conectarCassandra(e.cfg, "")
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	comandos := []string{ddlKeyspace(e.cfg)}
+	for _, modelo := range tabelasCassandra() {
+		comandos = append(comandos, tabelaCQLDe(modelo).ddl(e.cfg.Cassandra.Keyspace))
+	}
+
+	for _, cql := range comandos {
+		if err := session.Query(cql).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("%w\n%s", err, cql)
+		}
+	}
+	return nil
+}
+
+func (e *esquemaCassandra) Remover(ctx context.Context) error {
+	session, err := conectarCassandra(e.cfg, "")
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	return session.Query("DROP KEYSPACE IF EXISTS " + e.cfg.Cassandra.Keyspace).WithContext(ctx).Exec()
+}
+
+func (e *esquemaCassandra) Verificar(ctx context.Context) ([]string, error) {
+	session, err := conectarCassandra(e.cfg, "")
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	// Colunas existentes, por tabela
+	existentes := make(map[string]map[string]colunaCQL)
+	iter := session.Query(`
+		SELECT table_name, column_name, type, kind
+		FROM system_schema.columns
+		WHERE keyspace_name = ?
+	`, e.cfg.Cassandra.Keyspace).WithContext(ctx).Iter()
+
+	var tabela string
+	var coluna colunaCQL
+	for iter.Scan(&tabela, &coluna.nome, &coluna.tipo, &coluna.kind) {
+		if existentes[tabela] == nil {
+			existentes[tabela] = make(map[string]colunaCQL)
+		}
+		existentes[tabela][coluna.nome] = coluna
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	if len(existentes) == 0 {
+		return []string{fmt.Sprintf("keyspace %s não existe ou está vazio", e.cfg.Cassandra.Keyspace)}, nil
+	}
+
+	var divergencias []string
+	esperadas := make(map[string]bool)
+	for _, modelo := range tabelasCassandra() {
+		esperada := tabelaCQLDe(modelo)
+		esperadas[esperada.nome] = true
+		divergencias = append(divergencias, compararTabelaCQL(esperada, existentes[esperada.nome])...)
+	}
+
+	var extras []string
+	for nome := range existentes {
+		if !esperadas[nome] {
+			extras = append(extras, nome)
+		}
+	}
+	slices.Sort(extras)
+	for _, nome := range extras {
+		divergencias = append(divergencias, fmt.Sprintf("tabela %s existe, mas não é gerada", nome))
+	}
+	return divergencias, nil
+}
+
+// compararTabelaCQL lista as diferenças entre a tabela esperada e as
+// colunas encontradas no banco (nil quando a tabela não existe).
+func compararTabelaCQL(esperada tabelaCQL, existentes map[string]colunaCQL) []string {
+	if existentes == nil {
+		return []string{fmt.Sprintf("tabela %s não existe", esperada.nome)}
+	}
+
+	var divergencias []string
+	vistas := make(map[string]bool)
+	for _, c := range esperada.colunas {
+		vistas[c.nome] = true
+		atual, ok := existentes[c.nome]
+		switch {
+		case !ok:
+			divergencias = append(divergencias, fmt.Sprintf("%s.%s não existe (esperado %s)", esperada.nome, c.nome, c.tipo))
+		case atual.tipo != c.tipo:
+			divergencias = append(divergencias, fmt.Sprintf("%s.%s é %s (esperado %s)", esperada.nome, c.nome, atual.tipo, c.tipo))
+		case atual.kind != c.kind:
+			divergencias = append(divergencias, fmt.Sprintf("%s.%s é %s (esperado %s)", esperada.nome, c.nome, atual.kind, c.kind))
+		}
+	}
+
+	var extras []string
+	for nome := range existentes {
+		if !vistas[nome] {
+			extras = append(extras, nome)
+		}
+	}
+	slices.Sort(extras)
+	for _, nome := range extras {
+		divergencias = append(divergencias, fmt.Sprintf("%s.%s existe, mas não é gerada", esperada.nome, nome))
+	}
+	return divergencias
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gocql/gocql"
+)
+
+// CassandraSink grava cada tabela na tabela de mesmo nome do keyspace
+// configurado.
+//
+// O INSERT de cada tabela é montado uma única vez; o gocql o prepara no
+// primeiro uso e reaproveita o statement preparado nas execuções seguintes.
+// Com Cassandra.TamanhoBatch > 0, linhas da mesma partição (por exemplo, os
+// itens de uma nota) são agrupadas em UNLOGGED BATCH. Os comandos de um lote
+// são executados em paralelo, limitados a Cassandra.EmVoo simultâneos em
+// todo o sink.
+type CassandraSink struct {
+	cfg     *Config
+	session *gocql.Session
+
+	inserts sync.Map // tabela -> comando INSERT
+	emVoo   chan struct{}
+}
+
+func novoCassandraSink(cfg *Config) *CassandraSink {
+	return &CassandraSink{
+		cfg:   cfg,
+		emVoo: make(chan struct{}, cfg.Cassandra.EmVoo),
+	}
+}
+
+func (s *CassandraSink) Nome() string { return "Cassandra" }
+
+func (s *CassandraSink) Abrir(ctx context.Context) error {
+	session, err := conectarCassandra(s.cfg, s.cfg.Cassandra.Keyspace)
+	if err != nil {
+		return err
+	}
+	s.session = session
+	return nil
+}
+
+func (s *CassandraSink) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	if len(registros) == 0 {
+		return nil
+	}
+	cql := s.comandoInsert(tabela, registros[0])
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		falhas []FalhaRegistro
+	)
+	for _, grupo := range s.agrupar(registros) {
+		s.emVoo <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-s.emVoo
+				wg.Done()
+			}()
+
+			if err := s.executar(ctx, cql, grupo); err != nil {
+				mu.Lock()
+				for _, r := range grupo {
+					falhas = append(falhas, FalhaRegistro{Chave: chaveDe(r), Err: err})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(falhas) > 0 {
+		return &ErroLote{Tabela: tabela, Total: len(registros), Falhas: falhas}
+	}
+	return nil
+}
+
+// comandoInsert devolve o INSERT da tabela, montando-o no primeiro uso.
+func (s *CassandraSink) comandoInsert(tabela string, r Registro) string {
+	if cql, ok := s.inserts.Load(tabela); ok {
+		return cql.(string)
+	}
+	cql, _ := s.inserts.LoadOrStore(tabela, comandoInsert(tabela, colunasDe(r)))
+	return cql.(string)
+}
+
+// agrupar divide os registros em unidades de execução: um registro por
+// unidade, ou, com batch habilitado, até TamanhoBatch registros da mesma
+// partição, mantendo a ordem de chegada.
+func (s *CassandraSink) agrupar(registros []Registro) [][]Registro {
+	tamanho := s.cfg.Cassandra.TamanhoBatch
+	if tamanho <= 1 {
+		grupos := make([][]Registro, len(registros))
+		for i := range registros {
+			grupos[i] = registros[i : i+1]
+		}
+		return grupos
+	}
+
+	var grupos [][]Registro
+	abertos := make(map[string]int) // partição -> índice do grupo em formação
+	for _, r := range registros {
+		particao := particaoDe(r)
+		i, ok := abertos[particao]
+		if !ok || len(grupos[i]) >= tamanho {
+			i = len(grupos)
+			grupos = append(grupos, nil)
+			abertos[particao] = i
+		}
+		grupos[i] = append(grupos[i], r)
+	}
+	return grupos
+}
+
+// executar grava um grupo: um INSERT simples ou um UNLOGGED BATCH.
+func (s *CassandraSink) executar(ctx context.Context, cql string, grupo []Registro) error {
+	if len(grupo) == 1 {
+		return s.session.Query(cql, valoresDe(grupo[0])...).WithContext(ctx).Exec()
+	}
+
+	batch := s.session.NewBatch(gocql.UnloggedBatch).WithContext(ctx)
+	for _, r := range grupo {
+		batch.Query(cql, valoresDe(r)...)
+	}
+	return s.session.ExecuteBatch(batch)
+}
+
+func (s *CassandraSink) Flush(ctx context.Context) error { return nil }
+
+func (s *CassandraSink) Fechar(ctx context.Context) error {
+	if s.session != nil {
+		s.session.Close()
+	}
+	return nil
+}
+
+// particaoDe devolve os valores da chave de partição de um registro,
+// usados para agrupar linhas da mesma partição num batch.
+func particaoDe(r Registro) string {
+	v := reflect.ValueOf(r)
+	var partes []string
+	for _, c := range camposDe(v.Type()) {
+		if c.chave == chaveParticao {
+			partes = append(partes, fmt.Sprint(v.Field(c.indice).Interface()))
+		}
+	}
+	return strings.Join(partes, "|")
+}
+
+// comandoInsert monta o INSERT de uma tabela com um marcador por coluna.
+func comandoInsert(tabela string, colunas []string) string {
+	marcadores := strings.TrimSuffix(strings.Repeat("?, ", len(colunas)), ", ")
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tabela, strings.Join(colunas, ", "), marcadores)
+}
+
+// conectarCassandra abre uma sessão no keyspace informado; vazio conecta
+// sem keyspace, como é preciso para criá-lo.
+func conectarCassandra(cfg *Config, keyspace string) (*gocql.Session, error) {
+	cluster := gocql.NewCluster(cfg.Cassandra.Hosts...)
+	cluster.Keyspace = keyspace
+	cluster.Consistency = gocql.Quorum
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Conectado ao Cassandra com sucesso!")
+	return session, nil
+}
@@ -1,135 +1,157 @@
+// Gerador de dados de varejo para o comparativo MongoDB vs Cassandra.
+//
+// Uso:
+//
+//	go run . [flags]
+//	go run . --scale 10
+//	go run . --seed 42 --data-referencia 2024-12-31
+//	go run . --sinks cassandra
+//	go run . --popularidade-produtos zipf:1.1 --popularidade-clientes pareto:20/80
+//	go run . --notas-inicio 2022-01-01 --notas-crescimento 15 --notas-pico-black-friday 6
+//	go run . --documentos-mascara
+//	go run . --sinks postgres --postgres-uri postgres://localhost/varejo
+//	go run . --sinks csv --arquivos-dir dados --arquivos-gzip
+//	go run . --sinks jsonl,bson --arquivos-dir dump
+//	go run . --sinks parquet --parquet-linhas-grupo 500000
+//	go run . --config varejo.yaml --notas-fiscais 500000
+//	go run . --print-config
+//	go run . schema create|drop|check [flags]
+//	go run . load --load-recriar-schema
+//	go run . simulate --simulacao-ops 500 --simulacao-duracao 10m
+//	go run . bench --bench-repeticoes 20
+//	go run . bench compare antigo.json novo.json [flags]
+//	go run . report resultados/bench-20250101-120000.json
+//
+// Toda flag também pode ser definida pela variável de ambiente equivalente
+// com prefixo DATAGEN_ (por exemplo, DATAGEN_MONGO_URI para --mongo-uri).
 package main
 
 import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"os"
+	"slices"
 	"strings"
-	"sync"
 	"time"
-
-	"github.com/gocql/gocql"
-	"github.com/google/uuid"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-// Configurações
-const (
-	numProdutos    = 5000
-	numLojas       = 50
-	numPDVs        = 500
-	numCaixas      = 500
-	numClientes    = 25000
-	numFornecedores = 10000
-	numCidades     = 2000
-	numNotasFiscais = 100000
-	numItensNotaFiscal = 100000
-	
-	// Conexões para os bancos de dados
-	mongoURI      = "mongodb://localhost:27017"
-	cassandraHost = "127.0.0.1"
-	
-	// Nomes das databases
-	mongoDB       = "varejo"
-	cassandraKeyspace = "meu_keyspace"
-	
-	// Paralelismo para gerar dados mais rapidamente
-	numGoroutines = 10
 )
 
 // Estruturas de dados
+//
+// A tag bson define o nome da coluna em todos os destinos; a tag chave marca
+// a chave primária (particao e, quando houver, clustering).
 type Produto struct {
-	CodProduto     int     `bson:"cod_produto"`
-	NomProduto     string  `bson:"nom_produto"`
-	CodFornecedor  int     `bson:"cod_fornecedor"`
-	CodSetor       int     `bson:"cod_setor"`
-	CodUnidade     int     `bson:"cod_unidade"`
-	FlgFracionado  string  `bson:"flg_fracionado"`
-	VlrVenda       float64 `bson:"vlr_venda"`
-	VlrCusto       float64 `bson:"vlr_custo"`
-	VlrMedio       float64 `bson:"vlr_medio"`
-	CodPromocao    int     `bson:"cod_promocao,omitempty"`
-	VlrPromocao    float64 `bson:"vlr_promocao,omitempty"`
+	CodProduto    int     `bson:"cod_produto" chave:"particao"`
+	NomProduto    string  `bson:"nom_produto"`
+	CodFornecedor int     `bson:"cod_fornecedor"`
+	CodSetor      int     `bson:"cod_setor"`
+	CodUnidade    int     `bson:"cod_unidade"`
+	FlgFracionado string  `bson:"flg_fracionado"`
+	VlrVenda      float64 `bson:"vlr_venda"`
+	VlrCusto      float64 `bson:"vlr_custo"`
+	VlrMedio      float64 `bson:"vlr_medio"`
+	CodPromocao   int     `bson:"cod_promocao,omitempty"`
+	VlrPromocao   float64 `bson:"vlr_promocao,omitempty"`
+}
+
+type Setor struct {
+	CodSetor int    `bson:"cod_setor" chave:"particao"`
+	NomSetor string `bson:"nom_setor"`
+}
+
+type Unidade struct {
+	CodUnidade int    `bson:"cod_unidade" chave:"particao"`
+	SigUnidade string `bson:"sig_unidade"`
+	NomUnidade string `bson:"nom_unidade"`
+}
+
+type Promocao struct {
+	CodPromocao       int       `bson:"cod_promocao" chave:"particao"`
+	NomPromocao       string    `bson:"nom_promocao"`
+	DatInicioVigencia time.Time `bson:"dat_inicio_vigencia"`
+	DatFimVigencia    time.Time `bson:"dat_fim_vigencia"`
+	PctDesconto       float64   `bson:"pct_desconto"`
 }
 
 type Loja struct {
-	CodLoja      int    `bson:"cod_loja"`
-	NomLoja      string `bson:"nom_loja"`
-	CodEndereco  int    `bson:"cod_endereco"`
-	FlgMatriz    string `bson:"flg_matriz"`
+	CodLoja     int    `bson:"cod_loja" chave:"particao"`
+	NomLoja     string `bson:"nom_loja"`
+	NumCNPJ     string `bson:"num_cnpj"`
+	CodEndereco int    `bson:"cod_endereco"`
+	FlgMatriz   string `bson:"flg_matriz"`
 }
 
 type PDV struct {
-	CodPDV           int       `bson:"cod_pdv"`
-	NumRegistro      float64   `bson:"num_registro"`
+	CodPDV            int       `bson:"cod_pdv" chave:"particao"`
+	NumRegistro       float64   `bson:"num_registro"`
 	DatInicioVigencia time.Time `bson:"dat_inicio_vigencia"`
-	DatFimVigencia   time.Time `bson:"dat_fim_vigencia"`
-	NumNotaInicial   float64   `bson:"num_nota_inicial"`
-	NumNotaFinal     float64   `bson:"num_nota_final"`
-	CodLoja          int       `bson:"cod_loja"`
-	NumPDVLoja       float64   `bson:"num_pdv_loja"`
+	DatFimVigencia    time.Time `bson:"dat_fim_vigencia"`
+	NumNotaInicial    float64   `bson:"num_nota_inicial"`
+	NumNotaFinal      float64   `bson:"num_nota_final"`
+	CodLoja           int       `bson:"cod_loja"`
+	NumPDVLoja        float64   `bson:"num_pdv_loja"`
 }
 
 type Caixa struct {
-	CodCaixa  int    `bson:"cod_caixa"`
+	CodCaixa  int    `bson:"cod_caixa" chave:"particao"`
 	NomCaixa  string `bson:"nom_caixa"`
 	CodLoja   int    `bson:"cod_loja"`
 	FlgFerias string `bson:"flg_ferias"`
 }
 
 type Cidade struct {
-	CodIBGE    int    `bson:"cod_ibge"`
-	NomCidade  string `bson:"nom_cidade"`
-	NomEstado  string `bson:"nom_estado"`
-	NomRegiao  string `bson:"nom_regiao"`
-	NomPais    string `bson:"nom_pais"`
+	CodIBGE   int    `bson:"cod_ibge" chave:"particao"`
+	NomCidade string `bson:"nom_cidade"`
+	NomEstado string `bson:"nom_estado"`
+	NomRegiao string `bson:"nom_regiao"`
+	NomPais   string `bson:"nom_pais"`
 }
 
 type Endereco struct {
-	CodEndereco    int    `bson:"cod_endereco"`
-	NomLogradouro  string `bson:"nom_logradouro"`
-	NumLogradouro  string `bson:"num_logradouro"`
-	CodCEP         float64 `bson:"cod_cep"`
-	CodIBGE        int    `bson:"cod_ibge"`
-	FlgExterior    string `bson:"flg_exterior"`
-	TipLogradouro  string `bson:"tip_logradouro"`
+	CodEndereco   int     `bson:"cod_endereco" chave:"particao"`
+	NomLogradouro string  `bson:"nom_logradouro"`
+	NumLogradouro string  `bson:"num_logradouro"`
+	CodCEP        float64 `bson:"cod_cep"`
+	CodIBGE       int     `bson:"cod_ibge"`
+	FlgExterior   string  `bson:"flg_exterior"`
+	TipLogradouro string  `bson:"tip_logradouro"`
 }
 
 type Cliente struct {
-	CodCliente    int    `bson:"cod_cliente"`
+	CodCliente    int    `bson:"cod_cliente" chave:"particao"`
 	NomCliente    string `bson:"nom_cliente"`
+	NumCPF        string `bson:"num_cpf"`
 	FlgFidelizado string `bson:"flg_fidelizado"`
 	CodEndereco   int    `bson:"cod_endereco"`
 }
 
 type Fornecedor struct {
-	CodFornecedor  int    `bson:"cod_fornecedor"`
-	NomFornecedor  string `bson:"nom_fornecedor"`
-	FlgFatura      string `bson:"flg_fatura"`
-	NumDiasFatura  float64 `bson:"num_dias_fatura"`
+	CodFornecedor int     `bson:"cod_fornecedor" chave:"particao"`
+	NomFornecedor string  `bson:"nom_fornecedor"`
+	NumCNPJ       string  `bson:"num_cnpj"`
+	FlgFatura     string  `bson:"flg_fatura"`
+	NumDiasFatura float64 `bson:"num_dias_fatura"`
 }
 
 type NotaFiscal struct {
-	SeqNota      int       `bson:"seq_nota"`
-	CodPDV       int       `bson:"cod_pdv"`
-	CodCaixa     int       `bson:"cod_caixa"`
-	CodCliente   int       `bson:"cod_cliente"`
-	NumNota      float64   `bson:"num_nota"`
-	DatNota      time.Time `bson:"dat_nota"`
-	FlgEntrega   string    `bson:"flg_entrega"`
-	VlrNota      float64   `bson:"vlr_nota"`
-	VlrDinheiro  float64   `bson:"vlr_dinheiro"`
-	VlrTick      float64   `bson:"vlr_tick"`
-	VlrCartao    float64   `bson:"vlr_cartao"`
+	SeqNota     int       `bson:"seq_nota" chave:"particao"`
+	CodPDV      int       `bson:"cod_pdv"`
+	CodCaixa    int       `bson:"cod_caixa"`
+	CodCliente  int       `bson:"cod_cliente"`
+	NumNota     float64   `bson:"num_nota"`
+	DatNota     time.Time `bson:"dat_nota"`
+	FlgEntrega  string    `bson:"flg_entrega"`
+	VlrNota     float64   `bson:"vlr_nota"`
+	VlrDinheiro float64   `bson:"vlr_dinheiro"`
+	VlrTick     float64   `bson:"vlr_tick"`
+	VlrCartao   float64   `bson:"vlr_cartao"`
 }
 
 type ItemNotaFiscal struct {
-	SeqItemNota int     `bson:"seq_item_nota"`
-	SeqNota     int     `bson:"seq_nota"`
+	SeqItemNota int     `bson:"seq_item_nota" chave:"clustering"`
+	SeqNota     int     `bson:"seq_nota" chave:"particao"`
 	CodProduto  int     `bson:"cod_produto"`
 	QtdProduto  float64 `bson:"qtd_produto"`
 	VlrVenda    float64 `bson:"vlr_venda"`
@@ -138,58 +160,79 @@ type ItemNotaFiscal struct {
 	VlrPromocao float64 `bson:"vlr_promocao"`
 }
 
+// Tabela devolve o nome da coleção no MongoDB e da tabela no Cassandra
+func (Setor) Tabela() string          { return "setor" }
+func (Unidade) Tabela() string        { return "unidade" }
+func (Promocao) Tabela() string       { return "promocao" }
+func (Produto) Tabela() string        { return "produto" }
+func (Loja) Tabela() string           { return "loja" }
+func (PDV) Tabela() string            { return "pdv" }
+func (Caixa) Tabela() string          { return "caixa" }
+func (Cidade) Tabela() string         { return "cidade" }
+func (Endereco) Tabela() string       { return "endereco" }
+func (Cliente) Tabela() string        { return "cliente" }
+func (Fornecedor) Tabela() string     { return "fornecedor" }
+func (NotaFiscal) Tabela() string     { return "nota_fiscal" }
+func (ItemNotaFiscal) Tabela() string { return "item_nota_fiscal" }
+
 // Variáveis globais
 var (
-	estados = []string{
-		"AC", "AL", "AP", "AM", "BA", "CE", "DF", "ES", "GO", "MA",
-		"MT", "MS", "MG", "PA", "PB", "PR", "PE", "PI", "RJ", "RN",
-		"RS", "RO", "RR", "SC", "SP", "SE", "TO",
-	}
-	
-	regioes = []string{
-		"Norte", "Nordeste", "Centro-Oeste", "Sudeste", "Sul",
-	}
-	
 	tiposLogradouro = []string{
 		"R", "AV", "AL", "EST", "ROD", "PRÇ", "VL",
 	}
-	
-	setores = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
-	unidades = []int{1, 2, 3, 4, 5}
-	
+
+	// Setores e unidades são catálogos fixos: cod_setor e cod_unidade são a
+	// posição (a partir de 1) em cada lista.
+	setores = []string{
+		"Mercearia", "Hortifrúti", "Açougue", "Padaria", "Frios e Laticínios",
+		"Bebidas", "Limpeza", "Higiene e Beleza", "Congelados", "Bazar",
+	}
+
+	unidades = []struct{ sigla, nome string }{
+		{"UN", "Unidade"}, {"KG", "Quilograma"}, {"G", "Grama"}, {"L", "Litro"},
+		{"ML", "Mililitro"}, {"CX", "Caixa"}, {"PCT", "Pacote"}, {"DZ", "Dúzia"},
+	}
+
+	temasPromocoes = []string{
+		"Semana do Cliente", "Festival de Ofertas", "Queima de Estoque",
+		"Aniversário da Loja", "Leve Mais Pague Menos", "Ofertas de Verão",
+		"Volta às Aulas", "Feira de Hortifrúti", "Semana da Carne",
+		"Liquida Inverno", "Mês do Consumidor", "Ofertas de Fim de Ano",
+	}
+
 	nomesProdutos = []string{
 		"Arroz", "Feijão", "Macarrão", "Açúcar", "Café", "Leite", "Óleo",
 		"Farinha", "Sal", "Carne", "Frango", "Peixe", "Pão", "Cerveja",
 		"Refrigerante", "Suco", "Biscoito", "Chocolate", "Sorvete", "Sabão",
 		"Detergente", "Desinfetante", "Papel Higiênico", "Shampoo", "Condicionador",
 	}
-	
+
 	sobrenomesProdutos = []string{
 		"Tipo 1", "Premium", "Gold", "Silver", "Tradicional", "Especial",
 		"Extra", "Super", "Master", "Light", "Integral", "Natural",
 		"Original", "Fino", "Clássico", "Orgânico", "Zero", "Plus",
 		"Mega", "Ultra", "Soft", "Fresh", "Tropical", "Gourmet",
 	}
-	
+
 	marcasProdutos = []string{
-		"Nova Era", "Tradição", "Qualidade", "Campo Bom", "Delícia", 
-		"Saúde Total", "Sabor Perfeito", "MasterFood", "Naturalmente", 
+		"Nova Era", "Tradição", "Qualidade", "Campo Bom", "Delícia",
+		"Saúde Total", "Sabor Perfeito", "MasterFood", "Naturalmente",
 		"BomGosto", "AmigoDia", "CasaFeliz", "PuroBem", "DeliciaReal",
 	}
-	
+
 	sobrenomesPessoas = []string{
 		"Silva", "Santos", "Oliveira", "Souza", "Lima", "Pereira", "Ferreira",
 		"Costa", "Rodrigues", "Almeida", "Nascimento", "Carvalho", "Gomes",
 		"Martins", "Araújo", "Ribeiro", "Monteiro", "Cardoso", "Correia",
 	}
-	
+
 	nomesPessoas = []string{
 		"João", "Maria", "José", "Ana", "Pedro", "Paulo", "Carlos", "Marcos",
 		"Lucas", "Mateus", "Gabriel", "Rafael", "Daniel", "Antônio", "Fernando",
 		"Luiz", "Eduardo", "André", "Adriana", "Amanda", "Bruna", "Camila",
 		"Carolina", "Cláudia", "Débora", "Diana", "Eliana", "Fernanda", "Gabriela",
 	}
-	
+
 	nomesLogradouros = []string{
 		"Flores", "Palmeiras", "Ipê", "Jatobá", "Araçá", "Tucumã", "Brasil",
 		"Santos Dumont", "Getúlio Vargas", "JK", "Amazonas", "Rui Barbosa",
@@ -199,796 +242,704 @@ var (
 )
 
 func main() {
-	rand.Seed(time.Now().UnixNano())
-	
-	// Cria conexões com os bancos de dados
-	mongoClient, err := conectarMongoDB()
-	if err != nil {
-		log.Fatalf("Erro ao conectar ao MongoDB: %v", err)
+	comando, args := "gerar", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		comando, args = args[0], args[1:]
+	}
+
+	switch comando {
+	case "gerar":
+		executarGeracao(args)
+	case "schema":
+		executarSchema(args)
+	case "load":
+		executarCarga(args)
+	case "simulate":
+		executarSimulacao(args)
+	case "bench":
+		executarBench(args)
+	case "report":
+		executarRelatorio(args)
+	default:
+		log.Fatalf("Comando desconhecido: %q (use gerar, schema, load, simulate, bench ou report)", comando)
 	}
-	defer mongoClient.Disconnect(context.Background())
-	
-	cassandraSession, err := conectarCassandra()
+}
+
+// configurar carrega a configuração de um comando. Com --print-config,
+// imprime a configuração efetiva e devolve false.
+func configurar(nome string, args []string) (Config, bool) {
+	cfg, opcoes, err := carregarConfig(nome, args)
 	if err != nil {
-		log.Fatalf("Erro ao conectar ao Cassandra: %v", err)
+		log.Fatalf("Erro na configuração: %v", err)
+	}
+	if opcoes.imprimirConfig {
+		if err := imprimirConfig(os.Stdout, cfg); err != nil {
+			log.Fatalf("Erro ao imprimir configuração: %v", err)
+		}
+		return cfg, false
+	}
+	return cfg, true
+}
+
+// executarGeracao gera o conjunto de dados completo nos sinks configurados.
+func executarGeracao(args []string) {
+	cfg, ok := configurar("gerar", args)
+	if !ok {
+		return
 	}
-	defer cassandraSession.Close()
-	
-	// Contexto para operações do MongoDB
+
+	fmt.Printf("Semente: %d (use --seed %d para reproduzir esta execução)\n", cfg.Semente, cfg.Semente)
+
 	ctx := context.Background()
-	
+
+	// Abre os destinos configurados (MongoDB, Cassandra...)
+	sinks, err := criarSinks(&cfg)
+	if err != nil {
+		log.Fatalf("Erro na configuração dos sinks: %v", err)
+	}
+	saida := novaSaida(&cfg, sinks)
+	if err := saida.abrir(ctx); err != nil {
+		log.Fatalf("Erro ao abrir sinks: %v", err)
+	}
+	defer saida.fechar(ctx)
+
 	// Inicia contadores de progresso
 	fmt.Println("Iniciando geração de dados...")
-	
+	inicio := time.Now()
+	gerarDados(ctx, &cfg, saida)
+
+	if err := saida.flush(ctx); err != nil {
+		log.Fatalf("Erro ao descarregar sinks: %v", err)
+	}
+	fmt.Printf("Carga concluída em %v\n", time.Since(inicio).Round(time.Millisecond))
+
+	criarIndicesAdiados(ctx, &cfg)
+
+	fmt.Println("Geração de dados concluída com sucesso!")
+}
+
+// criarIndicesAdiados cria, depois da carga, os índices adiados por
+// --mongo-indices-apos-carga. gerar e load passam por aqui.
+func criarIndicesAdiados(ctx context.Context, cfg *Config) {
+	if !cfg.Mongo.IndicesAposCarga || !slices.Contains(cfg.Sinks, sinkMongoDB) {
+		return
+	}
+	inicio := time.Now()
+	if err := (&esquemaMongo{cfg: cfg}).criarIndices(ctx); err != nil {
+		log.Fatalf("Erro ao criar índices do MongoDB: %v", err)
+	}
+	fmt.Printf("Índices do MongoDB criados em %v\n", time.Since(inicio).Round(time.Millisecond))
+}
+
+// gerarDados gera todas as entidades, na ordem em que umas referenciam as
+// outras, e devolve as dimensões usadas durante a geração.
+func gerarDados(ctx context.Context, cfg *Config, saida *Saida) *Dimensoes {
+	dim := &Dimensoes{}
+
 	// Gera dados para Cidades
 	fmt.Println("Gerando cidades...")
-	gerarCidades(ctx, mongoClient, cassandraSession)
-	
+	gerarCidades(ctx, cfg, saida, dim)
+
 	// Gera dados para Endereços
 	fmt.Println("Gerando endereços...")
-	gerarEnderecos(ctx, mongoClient, cassandraSession)
-	
+	gerarEnderecos(ctx, cfg, saida, dim)
+
 	// Gera dados para Fornecedores
 	fmt.Println("Gerando fornecedores...")
-	gerarFornecedores(ctx, mongoClient, cassandraSession)
-	
+	gerarFornecedores(ctx, cfg, saida, dim)
+
+	// Gera dados para Setores, Unidades e Promoções
+	fmt.Println("Gerando setores, unidades e promoções...")
+	gerarSetores(ctx, cfg, saida, dim)
+	gerarUnidades(ctx, cfg, saida, dim)
+	gerarPromocoes(ctx, cfg, saida, dim)
+
 	// Gera dados para Produtos
 	fmt.Println("Gerando produtos...")
-	gerarProdutos(ctx, mongoClient, cassandraSession)
-	
+	gerarProdutos(ctx, cfg, saida, dim)
+
 	// Gera dados para Lojas
 	fmt.Println("Gerando lojas...")
-	gerarLojas(ctx, mongoClient, cassandraSession)
-	
+	gerarLojas(ctx, cfg, saida, dim)
+
 	// Gera dados para PDVs
 	fmt.Println("Gerando PDVs...")
-	gerarPDVs(ctx, mongoClient, cassandraSession)
-	
+	gerarPDVs(ctx, cfg, saida, dim)
+
 	// Gera dados para Caixas
 	fmt.Println("Gerando caixas...")
-	gerarCaixas(ctx, mongoClient, cassandraSession)
-	
+	gerarCaixas(ctx, cfg, saida, dim)
+
 	// Gera dados para Clientes
 	fmt.Println("Gerando clientes...")
-	gerarClientes(ctx, mongoClient, cassandraSession)
-	
+	gerarClientes(ctx, cfg, saida, dim)
+
 	// Gera dados para Notas Fiscais e Itens
 	fmt.Println("Gerando notas fiscais e itens...")
-	gerarNotasFiscaisEItens(ctx, mongoClient, cassandraSession)
-	
-	fmt.Println("Geração de dados concluída com sucesso!")
-}
+	maioresNumNota := gerarNotasFiscaisEItens(ctx, cfg, saida, dim)
 
-// Funções de conexão com bancos de dados
-func conectarMongoDB() (*mongo.Client, error) {
-	clientOptions := options.Client().ApplyURI(mongoURI)
-	client, err := mongo.Connect(context.Background(), clientOptions)
-	if err != nil {
-		return nil, err
-	}
-	
-	err = client.Ping(context.Background(), nil)
-	if err != nil {
-		return nil, err
-	}
-	
-	fmt.Println("Conectado ao MongoDB com sucesso!")
-	return client, nil
-}
+	// Registra de que geração vieram os dados e onde a numeração das notas
+	// parou
+	gerarMetadados(ctx, cfg, saida, maioresNumNota)
 
-func conectarCassandra() (*gocql.Session, error) {
-	cluster := gocql.NewCluster(cassandraHost)
-	cluster.Keyspace = cassandraKeyspace
-	cluster.Consistency = gocql.Quorum
-	
-	session, err := cluster.CreateSession()
-	if err != nil {
-		return nil, err
-	}
-	
-	fmt.Println("Conectado ao Cassandra com sucesso!")
-	return session, nil
+	return dim
 }
 
 // Funções geradoras de dados
-func gerarCidades(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-	collection := mongoClient.Database(mongoDB).Collection("cidade")
-	
-	var wg sync.WaitGroup
-	wg.Add(numGoroutines)
-	itemsPerGoroutine := numCidades / numGoroutines
-	
-	for g := 0; g < numGoroutines; g++ {
-		go func(goroutineID int) {
-			defer wg.Done()
-			
-			start := goroutineID * itemsPerGoroutine
-			end := start + itemsPerGoroutine
-			if goroutineID == numGoroutines-1 {
-				end = numCidades
-			}
-			
-			for i := start; i < end; i++ {
-				codIBGE := i + 1000000
-				estado := estados[rand.Intn(len(estados))]
-				regiao := regioes[rand.Intn(len(regioes))]
-				nomCidade := fmt.Sprintf("Cidade %d", i+1)
-				
-				cidade := Cidade{
-					CodIBGE:   codIBGE,
-					NomCidade: nomCidade,
-					NomEstado: estado,
-					NomRegiao: regiao,
-					NomPais:   "Brasil",
-				}
-				
-				// Insere no MongoDB
-				_, err := collection.InsertOne(ctx, cidade)
-				if err != nil {
-					log.Printf("Erro ao inserir cidade no MongoDB: %v", err)
-				}
-				
-				// Insere no Cassandra
-				err = cassandraSession.Query(`
-					INSERT INTO cidade (cod_ibge, nom_cidade, nom_estado, nom_regiao, nom_pais)
-					VALUES (?, ?, ?, ?, ?)
-				`, cidade.CodIBGE, cidade.NomCidade, cidade.NomEstado, cidade.NomRegiao, cidade.NomPais).Exec()
-				
-				if err != nil {
-					log.Printf("Erro ao inserir cidade no Cassandra: %v", err)
-				}
+func gerarCidades(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	dim.Cidades = make([]Cidade, cfg.Volumes.Cidades)
+	dim.PopulacaoCidades = make([]int, cfg.Volumes.Cidades)
+
+	// Amostra do cadastro, em que os municípios mais populosos têm mais
+	// chance de entrar
+	amostra := amostrarMunicipios(novoRand(cfg, "municipios", 0), cfg.Volumes.Cidades)
+	paraCadaBloco(cfg, "cidade", cfg.Volumes.Cidades, func(_ *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			m := amostra[i]
+
+			cidade := Cidade{
+				CodIBGE:   m.codIBGE,
+				NomCidade: m.nome,
+				NomEstado: m.uf,
+				NomRegiao: unidadesFederativas[m.uf].regiao,
+				NomPais:   "Brasil",
 			}
-		}(g)
-	}
-	
-	wg.Wait()
-	fmt.Printf("Geradas %d cidades\n", numCidades)
+
+			dim.Cidades[i] = cidade
+			dim.PopulacaoCidades[i] = m.populacao
+			lote.Adicionar(cidade)
+		}
+	})
+	dim.indexarCidades()
+
+	fmt.Printf("Geradas %d cidades\n", cfg.Volumes.Cidades)
 }
 
-func gerarEnderecos(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-	collection := mongoClient.Database(mongoDB).Collection("endereco")
-	
-	var wg sync.WaitGroup
-	wg.Add(numGoroutines)
-	
+func gerarEnderecos(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
 	// Precisamos de pelo menos tantos endereços quanto clientes + lojas
-	totalEnderecos := numClientes + numLojas
-	itemsPerGoroutine := totalEnderecos / numGoroutines
-	
-	for g := 0; g < numGoroutines; g++ {
-		go func(goroutineID int) {
-			defer wg.Done()
-			
-			start := goroutineID * itemsPerGoroutine
-			end := start + itemsPerGoroutine
-			if goroutineID == numGoroutines-1 {
-				end = totalEnderecos
-			}
-			
-			for i := start; i < end; i++ {
-				codEndereco := i + 1
-				nomLogradouro := nomesLogradouros[rand.Intn(len(nomesLogradouros))]
-				numLogradouro := fmt.Sprintf("%d", rand.Intn(1000)+1)
-				codCEP := float64(10000000 + rand.Intn(90000000))
-				codIBGE := rand.Intn(numCidades) + 1000000
-				flgExterior := "N"
-				tipLogradouro := tiposLogradouro[rand.Intn(len(tiposLogradouro))]
-				
-				endereco := Endereco{
-					CodEndereco:   codEndereco,
-					NomLogradouro: nomLogradouro,
-					NumLogradouro: numLogradouro,
-					CodCEP:        codCEP,
-					CodIBGE:       codIBGE,
-					FlgExterior:   flgExterior,
-					TipLogradouro: tipLogradouro,
-				}
-				
-				// Insere no MongoDB
-				_, err := collection.InsertOne(ctx, endereco)
-				if err != nil {
-					log.Printf("Erro ao inserir endereço no MongoDB: %v", err)
-				}
-				
-				// Insere no Cassandra
-				err = cassandraSession.Query(`
-					INSERT INTO endereco (cod_endereco, nom_logradouro, num_logradouro, cod_cep, cod_ibge, flg_exterior, tip_logradouro)
-					VALUES (?, ?, ?, ?, ?, ?, ?)
-				`, endereco.CodEndereco, endereco.NomLogradouro, endereco.NumLogradouro, 
-				endereco.CodCEP, endereco.CodIBGE, endereco.FlgExterior, endereco.TipLogradouro).Exec()
-				
-				if err != nil {
-					log.Printf("Erro ao inserir endereço no Cassandra: %v", err)
-				}
-			}
-		}(g)
+	totalEnderecos := cfg.Volumes.Clientes + cfg.Volumes.Lojas
+	dim.IBGEEnderecos = make([]int, totalEnderecos)
+
+	// Cada cidade recebe endereços na proporção da sua população
+	pesos := make([]float64, len(dim.Cidades))
+	for i, populacao := range dim.PopulacaoCidades {
+		pesos[i] = float64(populacao)
 	}
-	
-	wg.Wait()
+	cidades := novoSorteador(len(pesos), pesos)
+
+	paraCadaBloco(cfg, "endereco", totalEnderecos, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			codEndereco := i + 1
+			nomLogradouro := nomesLogradouros[rng.Intn(len(nomesLogradouros))]
+			numLogradouro := fmt.Sprintf("%d", rng.Intn(1000)+1)
+			cidade := dim.Cidades[cidades.sortear(rng)]
+			codCEP := float64(sortearCEP(rng, cidade.NomEstado))
+			codIBGE := cidade.CodIBGE
+			flgExterior := "N"
+			tipLogradouro := tiposLogradouro[rng.Intn(len(tiposLogradouro))]
+
+			endereco := Endereco{
+				CodEndereco:   codEndereco,
+				NomLogradouro: nomLogradouro,
+				NumLogradouro: numLogradouro,
+				CodCEP:        codCEP,
+				CodIBGE:       codIBGE,
+				FlgExterior:   flgExterior,
+				TipLogradouro: tipLogradouro,
+			}
+
+			dim.IBGEEnderecos[i] = codIBGE
+			lote.Adicionar(endereco)
+		}
+	})
+
 	fmt.Printf("Gerados %d endereços\n", totalEnderecos)
 }
 
-func gerarFornecedores(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-	collection := mongoClient.Database(mongoDB).Collection("fornecedor")
-	
-	var wg sync.WaitGroup
-	wg.Add(numGoroutines)
-	itemsPerGoroutine := numFornecedores / numGoroutines
-	
-	for g := 0; g < numGoroutines; g++ {
-		go func(goroutineID int) {
-			defer wg.Done()
-			
-			start := goroutineID * itemsPerGoroutine
-			end := start + itemsPerGoroutine
-			if goroutineID == numGoroutines-1 {
-				end = numFornecedores
+func gerarFornecedores(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	docs := novosDocumentos(cfg)
+
+	paraCadaBloco(cfg, "fornecedor", cfg.Volumes.Fornecedores, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			codFornecedor := i + 1
+			nome1 := nomesPessoas[rng.Intn(len(nomesPessoas))]
+			nome2 := sobrenomesPessoas[rng.Intn(len(sobrenomesPessoas))]
+			nomFornecedor := fmt.Sprintf("%s %s Ltda", nome1, nome2)
+			flgFatura := ""
+			numDiasFatura := float64(0)
+
+			if rng.Intn(2) == 1 {
+				flgFatura = "S"
+				numDiasFatura = float64(rng.Intn(30) + 1)
+			} else {
+				flgFatura = "N"
 			}
-			
-			for i := start; i < end; i++ {
-				codFornecedor := i + 1
-				nome1 := nomesPessoas[rand.Intn(len(nomesPessoas))]
-				nome2 := sobrenomesPessoas[rand.Intn(len(sobrenomesPessoas))]
-				nomFornecedor := fmt.Sprintf("%s %s Ltda", nome1, nome2)
-				flgFatura := ""
-				numDiasFatura := float64(0)
-				
-				if rand.Intn(2) == 1 {
-					flgFatura = "S"
-					numDiasFatura = float64(rand.Intn(30) + 1)
-				} else {
-					flgFatura = "N"
-				}
-				
-				fornecedor := Fornecedor{
-					CodFornecedor: codFornecedor,
-					NomFornecedor: nomFornecedor,
-					FlgFatura:     flgFatura,
-					NumDiasFatura: numDiasFatura,
-				}
-				
-				// Insere no MongoDB
-				_, err := collection.InsertOne(ctx, fornecedor)
-				if err != nil {
-					log.Printf("Erro ao inserir fornecedor no MongoDB: %v", err)
-				}
-				
-				// Insere no Cassandra
-				err = cassandraSession.Query(`
-					INSERT INTO fornecedor (cod_fornecedor, nom_fornecedor, flg_fatura, num_dias_fatura)
-					VALUES (?, ?, ?, ?)
-				`, fornecedor.CodFornecedor, fornecedor.NomFornecedor, fornecedor.FlgFatura, fornecedor.NumDiasFatura).Exec()
-				
-				if err != nil {
-					log.Printf("Erro ao inserir fornecedor no Cassandra: %v", err)
-				}
+
+			fornecedor := Fornecedor{
+				CodFornecedor: codFornecedor,
+				NomFornecedor: nomFornecedor,
+				NumCNPJ:       docs.cnpjFornecedor(codFornecedor),
+				FlgFatura:     flgFatura,
+				NumDiasFatura: numDiasFatura,
 			}
-		}(g)
+
+			lote.Adicionar(fornecedor)
+		}
+	})
+
+	fmt.Printf("Gerados %d fornecedores\n", cfg.Volumes.Fornecedores)
+}
+
+func gerarSetores(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	lote := saida.novoLote(ctx)
+	defer lote.Descarregar()
+
+	for i, nome := range setores {
+		lote.Adicionar(Setor{CodSetor: i + 1, NomSetor: nome})
+	}
+
+	fmt.Printf("Gerados %d setores\n", len(setores))
+}
+
+func gerarUnidades(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	lote := saida.novoLote(ctx)
+	defer lote.Descarregar()
+
+	for i, u := range unidades {
+		lote.Adicionar(Unidade{CodUnidade: i + 1, SigUnidade: u.sigla, NomUnidade: u.nome})
 	}
-	
-	wg.Wait()
-	fmt.Printf("Gerados %d fornecedores\n", numFornecedores)
+
+	fmt.Printf("Geradas %d unidades\n", len(unidades))
 }
 
-func gerarProdutos(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-	collection := mongoClient.Database(mongoDB).Collection("produto")
-	
-	var wg sync.WaitGroup
-	wg.Add(numGoroutines)
-	itemsPerGoroutine := numProdutos / numGoroutines
-	
-	for g := 0; g < numGoroutines; g++ {
-		go func(goroutineID int) {
-			defer wg.Done()
-			
-			start := goroutineID * itemsPerGoroutine
-			end := start + itemsPerGoroutine
-			if goroutineID == numGoroutines-1 {
-				end = numProdutos
+func gerarPromocoes(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	dim.Promocoes = make([]Promocao, cfg.Volumes.Promocoes)
+
+	paraCadaBloco(cfg, "promocao", cfg.Volumes.Promocoes, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			codPromocao := i + 1
+			tema := temasPromocoes[rng.Intn(len(temasPromocoes))]
+
+			// Campanhas de 1 a 4 semanas ao longo do último ano
+			dataInicio := inicioDiaLoja(cfg.dataReferencia().AddDate(0, -rng.Intn(12), -rng.Intn(30)))
+			dataFim := dataInicio.AddDate(0, 0, 7*(rng.Intn(4)+1))
+
+			// Desconto de 10% a 40%, em passos de 5%
+			pctDesconto := float64(10 + 5*rng.Intn(7))
+
+			promocao := Promocao{
+				CodPromocao:       codPromocao,
+				NomPromocao:       fmt.Sprintf("%s %d", tema, codPromocao),
+				DatInicioVigencia: dataInicio,
+				DatFimVigencia:    dataFim,
+				PctDesconto:       pctDesconto,
 			}
-			
-			for i := start; i < end; i++ {
-				codProduto := i + 1
-				
-				// Gera um nome de produto combinando elementos
-				nomeProduto := fmt.Sprintf("%s %s %s", 
-					marcasProdutos[rand.Intn(len(marcasProdutos))],
-					nomesProdutos[rand.Intn(len(nomesProdutos))],
-					sobrenomesProdutos[rand.Intn(len(sobrenomesProdutos))],
-				)
-				
-				codFornecedor := rand.Intn(numFornecedores) + 1
-				codSetor := setores[rand.Intn(len(setores))]
-				codUnidade := unidades[rand.Intn(len(unidades))]
-				
-				// Preços
-				vlrCusto := 5.0 + rand.Float64()*95.0 // De 5 a 100
-				vlrCusto = float64(int(vlrCusto*100)) / 100 // Arredonda para 2 casas decimais
-				
-				margem := 1.2 + rand.Float64()*0.8 // Margem de 20% a 100%
-				vlrVenda := vlrCusto * margem
-				vlrVenda = float64(int(vlrVenda*100)) / 100 // Arredonda para 2 casas decimais
-				
-				vlrMedio := (vlrCusto + vlrVenda) / 2
-				vlrMedio = float64(int(vlrMedio*100)) / 100 // Arredonda para 2 casas decimais
-				
-				// Flags e valores opcionais
-				flgFracionado := "N"
-				if rand.Intn(10) < 3 { // 30% dos produtos são fracionados
-					flgFracionado = "S"
-				}
-				
-				var codPromocao *int
-				var vlrPromocao *float64
-				
-				// 20% dos produtos estão em promoção
-				if rand.Intn(10) < 2 {
-					promo := rand.Intn(20) + 1
-					codPromocao = &promo
-					
-					promoVal := vlrVenda * 0.7 // 30% de desconto
-					promoVal = float64(int(promoVal*100)) / 100 // Arredonda para 2 casas decimais
-					vlrPromocao = &promoVal
-				}
-				
-				// Monta o objeto produto
-				produto := bson.M{
-					"cod_produto":    codProduto,
-					"nom_produto":    nomeProduto,
-					"cod_fornecedor": codFornecedor,
-					"cod_setor":      codSetor,
-					"cod_unidade":    codUnidade,
-					"flg_fracionado": flgFracionado,
-					"vlr_venda":      vlrVenda,
-					"vlr_custo":      vlrCusto,
-					"vlr_medio":      vlrMedio,
-				}
-				
-				if codPromocao != nil {
-					produto["cod_promocao"] = *codPromocao
-					produto["vlr_promocao"] = *vlrPromocao
-				}
-				
-				// Insere no MongoDB
-				_, err := collection.InsertOne(ctx, produto)
-				if err != nil {
-					log.Printf("Erro ao inserir produto no MongoDB: %v", err)
-				}
-				
-				// Insere no Cassandra
-				// Devido ao esquema do Cassandra, temos que lidar com nulos de outra forma
-				var promocaoCod int
-				var promocaoVlr float64
-				
-				if codPromocao != nil {
-					promocaoCod = *codPromocao
-					promocaoVlr = *vlrPromocao
-				}
-				
-				err = cassandraSession.Query(`
-					INSERT INTO produto (cod_produto, nom_produto, cod_fornecedor, cod_setor, cod_unidade, 
-					                     flg_fracionado, vlr_venda, vlr_custo, vlr_medio, cod_promocao, vlr_promocao)
-					VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-				`, codProduto, nomeProduto, codFornecedor, codSetor, codUnidade, 
-				flgFracionado, vlrVenda, vlrCusto, vlrMedio, promocaoCod, promocaoVlr).Exec()
-				
-				if err != nil {
-					log.Printf("Erro ao inserir produto no Cassandra: %v", err)
-				}
+
+			dim.Promocoes[i] = promocao
+			lote.Adicionar(promocao)
+		}
+	})
+
+	fmt.Printf("Geradas %d promoções\n", cfg.Volumes.Promocoes)
+}
+
+func gerarProdutos(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	dim.Produtos = make([]Produto, cfg.Volumes.Produtos)
+
+	paraCadaBloco(cfg, "produto", cfg.Volumes.Produtos, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			codProduto := i + 1
+
+			// Gera um nome de produto combinando elementos
+			nomeProduto := fmt.Sprintf("%s %s %s",
+				marcasProdutos[rng.Intn(len(marcasProdutos))],
+				nomesProdutos[rng.Intn(len(nomesProdutos))],
+				sobrenomesProdutos[rng.Intn(len(sobrenomesProdutos))],
+			)
+
+			codFornecedor := rng.Intn(cfg.Volumes.Fornecedores) + 1
+			codSetor := rng.Intn(len(setores)) + 1
+			codUnidade := rng.Intn(len(unidades)) + 1
+
+			// Preços
+			vlrCusto := 5.0 + rng.Float64()*95.0        // De 5 a 100
+			vlrCusto = float64(int(vlrCusto*100)) / 100 // Arredonda para 2 casas decimais
+
+			margem := 1.2 + rng.Float64()*0.8 // Margem de 20% a 100%
+			vlrVenda := vlrCusto * margem
+			vlrVenda = float64(int(vlrVenda*100)) / 100 // Arredonda para 2 casas decimais
+
+			vlrMedio := (vlrCusto + vlrVenda) / 2
+			vlrMedio = float64(int(vlrMedio*100)) / 100 // Arredonda para 2 casas decimais
+
+			// Flags e valores opcionais
+			flgFracionado := "N"
+			if rng.Intn(10) < 3 { // 30% dos produtos são fracionados
+				flgFracionado = "S"
 			}
-		}(g)
-	}
-	
-	wg.Wait()
-	fmt.Printf("Gerados %d produtos\n", numProdutos)
+
+			produto := Produto{
+				CodProduto:    codProduto,
+				NomProduto:    nomeProduto,
+				CodFornecedor: codFornecedor,
+				CodSetor:      codSetor,
+				CodUnidade:    codUnidade,
+				FlgFracionado: flgFracionado,
+				VlrVenda:      vlrVenda,
+				VlrCusto:      vlrCusto,
+				VlrMedio:      vlrMedio,
+			}
+
+			// 20% dos produtos estão em promoção. Sem promoção os campos
+			// ficam zerados: o MongoDB os omite e o Cassandra grava zero.
+			if rng.Intn(10) < 2 {
+				promocao := dim.Promocoes[rng.Intn(len(dim.Promocoes))]
+				produto.CodPromocao = promocao.CodPromocao
+
+				promoVal := vlrVenda * (1 - promocao.PctDesconto/100)
+				promoVal = float64(int(promoVal*100)) / 100 // Arredonda para 2 casas decimais
+				produto.VlrPromocao = promoVal
+			}
+
+			dim.Produtos[i] = produto
+			lote.Adicionar(produto)
+		}
+	})
+
+	fmt.Printf("Gerados %d produtos\n", cfg.Volumes.Produtos)
 }
 
-func gerarLojas(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-	collection := mongoClient.Database(mongoDB).Collection("loja")
-	
-	for i := 0; i < numLojas; i++ {
+func gerarLojas(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	docs := novosDocumentos(cfg)
+	lote := saida.novoLote(ctx)
+	defer lote.Descarregar()
+
+	for i := 0; i < cfg.Volumes.Lojas; i++ {
 		codLoja := i + 1
 		nomLoja := fmt.Sprintf("Loja %d", codLoja)
-		
+
 		// Usa os primeiros endereços para as lojas
 		codEndereco := i + 1
-		
+
 		flgMatriz := "N"
 		if i == 0 {
 			flgMatriz = "S"
 		}
-		
+
 		loja := Loja{
 			CodLoja:     codLoja,
 			NomLoja:     nomLoja,
+			NumCNPJ:     docs.cnpjLoja(codLoja),
 			CodEndereco: codEndereco,
 			FlgMatriz:   flgMatriz,
 		}
-		
-		// Insere no MongoDB
-		_, err := collection.InsertOne(ctx, loja)
-		if err != nil {
-			log.Printf("Erro ao inserir loja no MongoDB: %v", err)
-		}
-		
-		// Insere no Cassandra
-		err = cassandraSession.Query(`
-			INSERT INTO loja (cod_loja, nom_loja, cod_endereco, flg_matriz)
-			VALUES (?, ?, ?, ?)
-		`, loja.CodLoja, loja.NomLoja, loja.CodEndereco, loja.FlgMatriz).Exec()
-		
-		if err != nil {
-			log.Printf("Erro ao inserir loja no Cassandra: %v", err)
-		}
+
+		lote.Adicionar(loja)
+	}
+
+	fmt.Printf("Geradas %d lojas\n", cfg.Volumes.Lojas)
+}
+
+// vigenciaPDV sorteia a vigência de um PDV: 5 anos a partir de antes da
+// primeira nota, estendidos se preciso até o fim do último dia de notas no
+// relógio das lojas, para cobrir todo o período das notas.
+func vigenciaPDV(rng *rand.Rand, inicioNotas, fimNotas time.Time) (inicio, fim time.Time) {
+	inicio = inicioDiaLoja(inicioNotas.AddDate(0, -rng.Intn(12), -rng.Intn(30)))
+	fim = inicio.AddDate(5, 0, 0)
+	if limite := inicioDiaLoja(fimNotas.AddDate(0, 0, 1)); fim.Before(limite) {
+		fim = limite
 	}
-	
-	fmt.Printf("Geradas %d lojas\n", numLojas)
+	return inicio, fim
 }
 
-func gerarPDVs(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-	collection := mongoClient.Database(mongoDB).Collection("pdv")
-	
-	var wg sync.WaitGroup
-	wg.Add(numGoroutines)
-	itemsPerGoroutine := numPDVs / numGoroutines
-	
-	for g := 0; g < numGoroutines; g++ {
-		go func(goroutineID int) {
-			defer wg.Done()
-			
-			start := goroutineID * itemsPerGoroutine
-			end := start + itemsPerGoroutine
-			if goroutineID == numGoroutines-1 {
-				end = numPDVs
+func gerarPDVs(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	dim.PDVs = make([]PDV, cfg.Volumes.PDVs)
+	inicioNotas, fimNotas := cfg.periodoNotas()
+
+	paraCadaBloco(cfg, "pdv", cfg.Volumes.PDVs, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			codPDV := i + 1
+			numRegistro := float64(rng.Intn(9000) + 1000)
+
+			dataInicio, dataFim := vigenciaPDV(rng, inicioNotas, fimNotas)
+
+			// Notas fiscais
+			numNotaInicial := float64(rng.Intn(1000) + 1)
+			numNotaFinal := numNotaInicial + float64(rng.Intn(9000)+1000)
+
+			// Loja associada ao PDV
+			codLoja := rng.Intn(cfg.Volumes.Lojas) + 1
+			numPDVLoja := float64(rng.Intn(20) + 1) // Número do PDV dentro da loja
+
+			pdv := PDV{
+				CodPDV:            codPDV,
+				NumRegistro:       numRegistro,
+				DatInicioVigencia: dataInicio,
+				DatFimVigencia:    dataFim,
+				NumNotaInicial:    numNotaInicial,
+				NumNotaFinal:      numNotaFinal,
+				CodLoja:           codLoja,
+				NumPDVLoja:        numPDVLoja,
 			}
-			
-			for i := start; i < end; i++ {
-				codPDV := i + 1
-				numRegistro := float64(rand.Intn(9000) + 1000)
-				
-                // Datas de vigência
-                agora := time.Now()
-                dataInicio := agora.AddDate(-1, -rand.Intn(12), -rand.Intn(30))
-                dataFim := dataInicio.AddDate(5, 0, 0) // Vigência de 5 anos
-                
-                // Notas fiscais
-                numNotaInicial := float64(rand.Intn(1000) + 1)
-                numNotaFinal := numNotaInicial + float64(rand.Intn(9000) + 1000)
-                
-                // Loja associada ao PDV
-                codLoja := rand.Intn(numLojas) + 1
-                numPDVLoja := float64(rand.Intn(20) + 1) // Número do PDV dentro da loja
-                
-                pdv := PDV{
-                    CodPDV:           codPDV,
-                    NumRegistro:      numRegistro,
-                    DatInicioVigencia: dataInicio,
-                    DatFimVigencia:   dataFim,
-                    NumNotaInicial:   numNotaInicial,
-                    NumNotaFinal:     numNotaFinal,
-                    CodLoja:          codLoja,
-                    NumPDVLoja:       numPDVLoja,
-                }
-                
-                // Insere no MongoDB
-                _, err := collection.InsertOne(ctx, pdv)
-                if err != nil {
-                    log.Printf("Erro ao inserir PDV no MongoDB: %v", err)
-                }
-                
-                // Insere no Cassandra
-                err = cassandraSession.Query(`
-                    INSERT INTO pdv (cod_pdv, num_registro, dat_inicio_vigencia, dat_fim_vigencia, 
-                                   num_nota_inicial, num_nota_final, cod_loja, num_pdv_loja)
-                    VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-                `, pdv.CodPDV, pdv.NumRegistro, pdv.DatInicioVigencia, pdv.DatFimVigencia,
-                pdv.NumNotaInicial, pdv.NumNotaFinal, pdv.CodLoja, pdv.NumPDVLoja).Exec()
-                
-                if err != nil {
-                    log.Printf("Erro ao inserir PDV no Cassandra: %v", err)
-                }
-            }
-        }(g)
-    }
-    
-    wg.Wait()
-    fmt.Printf("Gerados %d PDVs\n", numPDVs)
+
+			dim.PDVs[i] = pdv
+			lote.Adicionar(pdv)
+		}
+	})
+	dim.indexarPDVs()
+
+	fmt.Printf("Gerados %d PDVs\n", cfg.Volumes.PDVs)
 }
 
-func gerarCaixas(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-    collection := mongoClient.Database(mongoDB).Collection("caixa")
-    
-    var wg sync.WaitGroup
-    wg.Add(numGoroutines)
-    itemsPerGoroutine := numCaixas / numGoroutines
-    
-    for g := 0; g < numGoroutines; g++ {
-        go func(goroutineID int) {
-            defer wg.Done()
-            
-            start := goroutineID * itemsPerGoroutine
-            end := start + itemsPerGoroutine
-            if goroutineID == numGoroutines-1 {
-                end = numCaixas
-            }
-            
-            for i := start; i < end; i++ {
-                codCaixa := i + 1
-                
-                // Nome do operador de caixa
-                nome := nomesPessoas[rand.Intn(len(nomesPessoas))]
-                sobrenome := sobrenomesPessoas[rand.Intn(len(sobrenomesPessoas))]
-                nomCaixa := fmt.Sprintf("%s %s", nome, sobrenome)
-                
-                // Loja associada ao caixa
-                codLoja := rand.Intn(numLojas) + 1
-                
-                // Situação de férias
-                flgFerias := "N"
-                if rand.Intn(10) < 1 { // 10% dos caixas estão de férias
-                    flgFerias = "S"
-                }
-                
-                caixa := Caixa{
-                    CodCaixa: codCaixa,
-                    NomCaixa: nomCaixa,
-                    CodLoja:  codLoja,
-                    FlgFerias: flgFerias,
-                }
-                
-                // Insere no MongoDB
-                _, err := collection.InsertOne(ctx, caixa)
-                if err != nil {
-                    log.Printf("Erro ao inserir caixa no MongoDB: %v", err)
-                }
-                
-                // Insere no Cassandra
-                err = cassandraSession.Query(`
-                    INSERT INTO caixa (cod_caixa, nom_caixa, cod_loja, flg_ferias)
-                    VALUES (?, ?, ?, ?)
-                `, caixa.CodCaixa, caixa.NomCaixa, caixa.CodLoja, caixa.FlgFerias).Exec()
-                
-                if err != nil {
-                    log.Printf("Erro ao inserir caixa no Cassandra: %v", err)
-                }
-            }
-        }(g)
-    }
-    
-    wg.Wait()
-    fmt.Printf("Gerados %d caixas\n", numCaixas)
+func gerarCaixas(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	dim.Caixas = make([]Caixa, cfg.Volumes.Caixas)
+
+	paraCadaBloco(cfg, "caixa", cfg.Volumes.Caixas, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			codCaixa := i + 1
+
+			// Nome do operador de caixa
+			nome := nomesPessoas[rng.Intn(len(nomesPessoas))]
+			sobrenome := sobrenomesPessoas[rng.Intn(len(sobrenomesPessoas))]
+			nomCaixa := fmt.Sprintf("%s %s", nome, sobrenome)
+
+			// Loja associada ao caixa
+			codLoja := rng.Intn(cfg.Volumes.Lojas) + 1
+
+			// Situação de férias
+			flgFerias := "N"
+			if rng.Intn(10) < 1 { // 10% dos caixas estão de férias
+				flgFerias = "S"
+			}
+
+			caixa := Caixa{
+				CodCaixa:  codCaixa,
+				NomCaixa:  nomCaixa,
+				CodLoja:   codLoja,
+				FlgFerias: flgFerias,
+			}
+
+			dim.Caixas[i] = caixa
+			lote.Adicionar(caixa)
+		}
+	})
+	dim.indexarCaixas()
+
+	fmt.Printf("Gerados %d caixas\n", cfg.Volumes.Caixas)
 }
 
-func gerarClientes(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-    collection := mongoClient.Database(mongoDB).Collection("cliente")
-    
-    var wg sync.WaitGroup
-    wg.Add(numGoroutines)
-    itemsPerGoroutine := numClientes / numGoroutines
-    
-    for g := 0; g < numGoroutines; g++ {
-        go func(goroutineID int) {
-            defer wg.Done()
-            
-            start := goroutineID * itemsPerGoroutine
-            end := start + itemsPerGoroutine
-            if goroutineID == numGoroutines-1 {
-                end = numClientes
-            }
-            
-            for i := start; i < end; i++ {
-                codCliente := i + 1
-                
-                // Nome do cliente
-                nome := nomesPessoas[rand.Intn(len(nomesPessoas))]
-                sobrenome := sobrenomesPessoas[rand.Intn(len(sobrenomesPessoas))]
-                nomCliente := fmt.Sprintf("%s %s", nome, sobrenome)
-                
-                // Status de fidelização
-                flgFidelizado := "N"
-                if rand.Intn(10) < 4 { // 40% dos clientes são fidelizados
-                    flgFidelizado = "S"
-                }
-                
-                // Endereço do cliente (após os endereços das lojas)
-                codEndereco := numLojas + i + 1
-                
-                cliente := Cliente{
-                    CodCliente:    codCliente,
-                    NomCliente:    nomCliente,
-                    FlgFidelizado: flgFidelizado,
-                    CodEndereco:   codEndereco,
-                }
-                
-                // Insere no MongoDB
-                _, err := collection.InsertOne(ctx, cliente)
-                if err != nil {
-                    log.Printf("Erro ao inserir cliente no MongoDB: %v", err)
-                }
-                
-                // Insere no Cassandra
-                err = cassandraSession.Query(`
-                    INSERT INTO cliente (cod_cliente, nom_cliente, flg_fidelizado, cod_endereco)
-                    VALUES (?, ?, ?, ?)
-                `, cliente.CodCliente, cliente.NomCliente, cliente.FlgFidelizado, cliente.CodEndereco).Exec()
-                
-                if err != nil {
-                    log.Printf("Erro ao inserir cliente no Cassandra: %v", err)
-                }
-            }
-        }(g)
-    }
-    
-    wg.Wait()
-    fmt.Printf("Gerados %d clientes\n", numClientes)
+func gerarClientes(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) {
+	docs := novosDocumentos(cfg)
+
+	paraCadaBloco(cfg, "cliente", cfg.Volumes.Clientes, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			codCliente := i + 1
+
+			// Nome do cliente
+			nome := nomesPessoas[rng.Intn(len(nomesPessoas))]
+			sobrenome := sobrenomesPessoas[rng.Intn(len(sobrenomesPessoas))]
+			nomCliente := fmt.Sprintf("%s %s", nome, sobrenome)
+
+			// Status de fidelização
+			flgFidelizado := "N"
+			if rng.Intn(10) < 4 { // 40% dos clientes são fidelizados
+				flgFidelizado = "S"
+			}
+
+			// Endereço do cliente (após os endereços das lojas)
+			codEndereco := cfg.Volumes.Lojas + i + 1
+
+			cliente := Cliente{
+				CodCliente:    codCliente,
+				NomCliente:    nomCliente,
+				NumCPF:        docs.cpfCliente(codCliente),
+				FlgFidelizado: flgFidelizado,
+				CodEndereco:   codEndereco,
+			}
+
+			lote.Adicionar(cliente)
+			lote.Adicionar(ClientePorCPF{
+				NumCPF:     cliente.NumCPF,
+				CodCliente: codCliente,
+				NomCliente: nomCliente,
+			})
+			if flgFidelizado == "S" {
+				cidade := dim.cidadeDoEndereco(codEndereco)
+				lote.Adicionar(ClienteFidelizadoPorCidade{
+					Cidade:     cidade.NomCidade,
+					CodCliente: codCliente,
+					Estado:     cidade.NomEstado,
+					NomCliente: nomCliente,
+				})
+			}
+		}
+	})
+
+	fmt.Printf("Gerados %d clientes\n", cfg.Volumes.Clientes)
 }
 
-func gerarNotasFiscaisEItens(ctx context.Context, mongoClient *mongo.Client, cassandraSession *gocql.Session) {
-    colecaoNotas := mongoClient.Database(mongoDB).Collection("nota_fiscal")
-    colecaoItens := mongoClient.Database(mongoDB).Collection("item_nota_fiscal")
-    
-    var wg sync.WaitGroup
-    wg.Add(numGoroutines)
-    itemsPerGoroutine := numNotasFiscais / numGoroutines
-    
-    // Precisamos de produtos pré-carregados para associar às notas
-    produtos := make([]Produto, 0, numProdutos)
-    cursor, err := mongoClient.Database(mongoDB).Collection("produto").Find(ctx, bson.M{})
-    if err != nil {
-        log.Printf("Erro ao buscar produtos: %v", err)
-        return
-    }
-    
-    if err = cursor.All(ctx, &produtos); err != nil {
-        log.Printf("Erro ao decodificar produtos: %v", err)
-        return
-    }
-    
-    for g := 0; g < numGoroutines; g++ {
-        go func(goroutineID int) {
-            defer wg.Done()
-            
-            start := goroutineID * itemsPerGoroutine
-            end := start + itemsPerGoroutine
-            if goroutineID == numGoroutines-1 {
-                end = numNotasFiscais
-            }
-            
-            for i := start; i < end; i++ {
-                seqNota := i + 1
-                
-                // Associações aleatórias
-                codPDV := rand.Intn(numPDVs) + 1
-                codCaixa := rand.Intn(numCaixas) + 1
-                codCliente := rand.Intn(numClientes) + 1
-                
-                // Dados da nota
-                numNota := float64(100000 + rand.Intn(900000))
-                datNota := time.Now().AddDate(0, -rand.Intn(12), -rand.Intn(30))
-                
-                flgEntrega := "N"
-                if rand.Intn(10) < 2 { // 20% com entrega
-                    flgEntrega = "S"
-                }
-                
-                // Cria a nota
-                notaFiscal := NotaFiscal{
-                    SeqNota:     seqNota,
-                    CodPDV:      codPDV,
-                    CodCaixa:    codCaixa,
-                    CodCliente:  codCliente,
-                    NumNota:     numNota,
-                    DatNota:     datNota,
-                    FlgEntrega:  flgEntrega,
-                    VlrNota:     0, // Será calculado com base nos itens
-                    VlrDinheiro: 0,
-                    VlrTick:     0,
-                    VlrCartao:   0,
-                }
-                
-                // Gera itens para a nota (entre 1 e 15 itens por nota)
-                numItens := rand.Intn(15) + 1
-                itensNota := make([]ItemNotaFiscal, 0, numItens)
-                
-                for j := 0; j < numItens; j++ {
-                    // Seleciona um produto aleatório
-                    produtoIdx := rand.Intn(len(produtos))
-                    produto := produtos[produtoIdx]
-                    
-                    // Quantidade vendida (entre 1 e 10, com decimais para produtos fracionados)
-                    qtdProduto := float64(rand.Intn(10) + 1)
-                    if produto.FlgFracionado == "S" {
-                        // Adiciona fração para produtos fracionados
-                        qtdProduto += float64(rand.Intn(10)) / 10
-                    }
-                    
-                    // Valor de venda (usa o de promoção se existir)
-                    vlrVenda := produto.VlrVenda
-                    if produto.VlrPromocao > 0 {
-                        vlrVenda = produto.VlrPromocao
-                    }
-                    
-                    item := ItemNotaFiscal{
-                        SeqItemNota: j + 1,
-                        SeqNota:     seqNota,
-                        CodProduto:  produto.CodProduto,
-                        QtdProduto:  qtdProduto,
-                        VlrVenda:    vlrVenda,
-                        VlrCusto:    produto.VlrCusto,
-                        VlrMedio:    produto.VlrMedio,
-                        VlrPromocao: produto.VlrPromocao,
-                    }
-                    
-                    itensNota = append(itensNota, item)
-                    
-                    // Acumula valor da nota
-                    notaFiscal.VlrNota += vlrVenda * qtdProduto
-                }
-                
-                // Arredonda o valor total
-                notaFiscal.VlrNota = float64(int(notaFiscal.VlrNota*100)) / 100
-                
-                // Distribui o pagamento entre as formas
-                formaPgto := rand.Intn(3)
-                switch formaPgto {
-                case 0: // Dinheiro
-                    notaFiscal.VlrDinheiro = notaFiscal.VlrNota
-                case 1: // Ticket
-                    notaFiscal.VlrTick = notaFiscal.VlrNota
-                case 2: // Cartão
-                    notaFiscal.VlrCartao = notaFiscal.VlrNota
-                }
-                
-                // Insere a nota no MongoDB
-                _, err := colecaoNotas.InsertOne(ctx, notaFiscal)
-                if err != nil {
-                    log.Printf("Erro ao inserir nota fiscal no MongoDB: %v", err)
-                    continue
-                }
-                
-                // Insere a nota no Cassandra
-                err = cassandraSession.Query(`
-                    INSERT INTO nota_fiscal (seq_nota, cod_pdv, cod_caixa, cod_cliente, num_nota, 
-                                           dat_nota, flg_entrega, vlr_nota, vlr_dinheiro, vlr_tick, vlr_cartao)
-                    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-                `, notaFiscal.SeqNota, notaFiscal.CodPDV, notaFiscal.CodCaixa, notaFiscal.CodCliente, 
-                notaFiscal.NumNota, notaFiscal.DatNota, notaFiscal.FlgEntrega, notaFiscal.VlrNota, 
-                notaFiscal.VlrDinheiro, notaFiscal.VlrTick, notaFiscal.VlrCartao).Exec()
-                
-                if err != nil {
-                    log.Printf("Erro ao inserir nota fiscal no Cassandra: %v", err)
-                }
-                
-                // Insere os itens da nota
-                for _, item := range itensNota {
-                    // MongoDB
-                    _, err := colecaoItens.InsertOne(ctx, item)
-                    if err != nil {
-                        log.Printf("Erro ao inserir item de nota fiscal no MongoDB: %v", err)
-                    }
-                    
-                    // Cassandra
-                    err = cassandraSession.Query(`
-                        INSERT INTO item_nota_fiscal (seq_item_nota, seq_nota, cod_produto, qtd_produto, 
-                                                    vlr_venda, vlr_custo, vlr_medio, vlr_promocao)
-                        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-                    `, item.SeqItemNota, item.SeqNota, item.CodProduto, item.QtdProduto,
-                    item.VlrVenda, item.VlrCusto, item.VlrMedio, item.VlrPromocao).Exec()
-                    
-                    if err != nil {
-                        log.Printf("Erro ao inserir item de nota fiscal no Cassandra: %v", err)
-                    }
-                }
-                
-                // Feedback de progresso a cada 1000 notas
-                if i%1000 == 0 && i > 0 {
-                    fmt.Printf("Geradas %d notas fiscais...\n", i)
-                }
-            }
-        }(g)
-    }
-    
-    wg.Wait()
-    fmt.Printf("Geradas %d notas fiscais com itens\n", numNotasFiscais)
+// gerarNotasFiscaisEItens gera as notas planejadas e devolve o maior num_nota
+// de cada PDV com notas.
+func gerarNotasFiscaisEItens(ctx context.Context, cfg *Config, saida *Saida, dim *Dimensoes) map[int]float64 {
+	sorteadores := novosSorteadoresNotas(cfg, dim)
+	cabecalhos, err := planejarNotas(cfg, dim, sorteadores, novoCalendarioVendas(cfg))
+	if err != nil {
+		log.Fatalf("Erro ao planejar as notas fiscais: %v", err)
+	}
+
+	paraCadaBloco(cfg, "nota_fiscal", cfg.Volumes.NotasFiscais, func(rng *rand.Rand, inicio, fim int) {
+		lote := saida.novoLote(ctx)
+		defer lote.Descarregar()
+
+		for i := inicio; i < fim; i++ {
+			seqNota := i + 1
+
+			// PDV, caixa, data e número já planejados; o cliente segue a
+			// popularidade configurada
+			cabecalho := cabecalhos[i]
+			codCliente := sorteadores.clientes.sortear(rng) + 1
+			datNota := cabecalho.data()
+
+			flgEntrega := "N"
+			if rng.Intn(10) < 2 { // 20% com entrega
+				flgEntrega = "S"
+			}
+
+			// Cria a nota
+			notaFiscal := NotaFiscal{
+				SeqNota:     seqNota,
+				CodPDV:      int(cabecalho.codPDV),
+				CodCaixa:    int(cabecalho.codCaixa),
+				CodCliente:  codCliente,
+				NumNota:     float64(cabecalho.numNota),
+				DatNota:     datNota,
+				FlgEntrega:  flgEntrega,
+				VlrNota:     0, // Será calculado com base nos itens
+				VlrDinheiro: 0,
+				VlrTick:     0,
+				VlrCartao:   0,
+			}
+
+			// Gera itens para a nota (entre 1 e MaxItensPorNota itens por nota)
+			numItens := rng.Intn(cfg.Volumes.MaxItensPorNota) + 1
+			itensNota := make([]ItemNotaFiscal, 0, numItens)
+
+			for j := 0; j < numItens; j++ {
+				// Seleciona um produto pela popularidade
+				produto := dim.Produtos[sorteadores.produtos.sortear(rng)]
+
+				// Quantidade vendida (entre 1 e 10, com decimais para produtos fracionados)
+				qtdProduto := float64(rng.Intn(10) + 1)
+				if produto.FlgFracionado == "S" {
+					// Adiciona fração para produtos fracionados
+					qtdProduto += float64(rng.Intn(10)) / 10
+				}
+
+				// Valor de venda (usa o de promoção se ela vigorar na data da nota)
+				vlrVenda := produto.VlrVenda
+				vlrPromocao := 0.0
+				if produto.CodPromocao > 0 && dim.promocaoVigente(produto.CodPromocao, datNota) {
+					vlrVenda = produto.VlrPromocao
+					vlrPromocao = produto.VlrPromocao
+				}
+
+				item := ItemNotaFiscal{
+					SeqItemNota: j + 1,
+					SeqNota:     seqNota,
+					CodProduto:  produto.CodProduto,
+					QtdProduto:  qtdProduto,
+					VlrVenda:    vlrVenda,
+					VlrCusto:    produto.VlrCusto,
+					VlrMedio:    produto.VlrMedio,
+					VlrPromocao: vlrPromocao,
+				}
+
+				itensNota = append(itensNota, item)
+
+				// Acumula valor da nota
+				notaFiscal.VlrNota += vlrVenda * qtdProduto
+			}
+
+			// Arredonda o valor total
+			notaFiscal.VlrNota = float64(int(notaFiscal.VlrNota*100)) / 100
+
+			// Distribui o pagamento entre as formas
+			formaPgto := rng.Intn(3)
+			switch formaPgto {
+			case 0: // Dinheiro
+				notaFiscal.VlrDinheiro = notaFiscal.VlrNota
+			case 1: // Ticket
+				notaFiscal.VlrTick = notaFiscal.VlrNota
+			case 2: // Cartão
+				notaFiscal.VlrCartao = notaFiscal.VlrNota
+			}
+
+			// A nota entra no lote antes dos itens, que a referenciam
+			lote.Adicionar(notaFiscal)
+			for _, item := range itensNota {
+				lote.Adicionar(item)
+			}
+
+			// Tabelas de consulta do Cassandra, com as junções já resolvidas
+			cidade := dim.cidadeDoEndereco(cfg.Volumes.Lojas + codCliente)
+			lote.Adicionar(NotaFiscalPorEstado{
+				Estado:  cidade.NomEstado,
+				SeqNota: seqNota,
+				DatNota: datNota,
+				VlrNota: notaFiscal.VlrNota,
+			})
+			for _, item := range itensNota {
+				produto := dim.Produtos[item.CodProduto-1]
+				lote.Adicionar(ItemNotaFiscalPorProduto{
+					ProdutoNome: produto.NomProduto,
+					SeqNota:     seqNota,
+					SeqItemNota: item.SeqItemNota,
+					CodProduto:  item.CodProduto,
+					QtdProduto:  item.QtdProduto,
+					VlrVenda:    item.VlrVenda,
+				})
+				lote.Adicionar(ItemNotaFiscalPorSetor{
+					Setor:       setores[produto.CodSetor-1],
+					SeqNota:     seqNota,
+					SeqItemNota: item.SeqItemNota,
+					CodProduto:  item.CodProduto,
+					VlrVenda:    item.VlrVenda,
+					VlrCusto:    item.VlrCusto,
+					VlrLucro:    math.Round((item.VlrVenda-item.VlrCusto)*100) / 100,
+				})
+			}
+
+			// Feedback de progresso a cada 1000 notas
+			if i%1000 == 0 && i > 0 {
+				fmt.Printf("Geradas %d notas fiscais...\n", i)
+			}
+		}
+	})
+
+	fmt.Printf("Geradas %d notas fiscais com itens\n", cfg.Volumes.NotasFiscais)
+	return maioresNumNota(cabecalhos)
 }
-                                    
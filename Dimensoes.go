@@ -0,0 +1,75 @@
+package main
+
+import "time"
+
+// Dimensoes guarda o que as etapas seguintes da geração precisam consultar
+// das entidades já geradas: as promoções aplicadas aos produtos, os produtos
+// vendidos nas notas, os PDVs e caixas de cada loja e a cidade de cada
+// endereço, usada nas tabelas de consulta do Cassandra.
+//
+// Cada gerar* preenche a sua parte antes de a próxima etapa começar, então
+// as leituras concorrentes das etapas seguintes dispensam sincronização.
+type Dimensoes struct {
+	Cidades   []Cidade
+	Promocoes []Promocao
+	Produtos  []Produto
+	PDVs      []PDV
+	Caixas    []Caixa
+
+	// PDVsPorLoja[cod_loja] são os cod_pdv da loja, em ordem crescente.
+	PDVsPorLoja map[int][]int
+	// CaixasEmServico[cod_loja] são os cod_caixa da loja que não estão de
+	// férias, em ordem crescente.
+	CaixasEmServico map[int][]int
+
+	// PopulacaoCidades[i] é a população de Cidades[i], que pondera a
+	// distribuição dos endereços.
+	PopulacaoCidades []int
+
+	// IBGEEnderecos[cod_endereco-1] é o cod_ibge do endereço.
+	IBGEEnderecos []int
+
+	indiceCidades map[int]int // cod_ibge -> posição em Cidades
+}
+
+// indexarCidades monta o índice por código IBGE depois de gerar as cidades.
+func (d *Dimensoes) indexarCidades() {
+	d.indiceCidades = make(map[int]int, len(d.Cidades))
+	for i, c := range d.Cidades {
+		d.indiceCidades[c.CodIBGE] = i
+	}
+}
+
+// indexarPDVs agrupa os PDVs por loja depois de gerá-los.
+func (d *Dimensoes) indexarPDVs() {
+	d.PDVsPorLoja = make(map[int][]int)
+	for _, p := range d.PDVs {
+		d.PDVsPorLoja[p.CodLoja] = append(d.PDVsPorLoja[p.CodLoja], p.CodPDV)
+	}
+}
+
+// indexarCaixas agrupa por loja os caixas em serviço depois de gerá-los.
+func (d *Dimensoes) indexarCaixas() {
+	d.CaixasEmServico = make(map[int][]int)
+	for _, c := range d.Caixas {
+		if c.FlgFerias == "N" {
+			d.CaixasEmServico[c.CodLoja] = append(d.CaixasEmServico[c.CodLoja], c.CodCaixa)
+		}
+	}
+}
+
+// lojaEmiteNotas informa se a loja tem PDV e caixa em serviço.
+func (d *Dimensoes) lojaEmiteNotas(codLoja int) bool {
+	return len(d.PDVsPorLoja[codLoja]) > 0 && len(d.CaixasEmServico[codLoja]) > 0
+}
+
+// cidadeDoEndereco devolve a cidade de um endereço já gerado.
+func (d *Dimensoes) cidadeDoEndereco(codEndereco int) Cidade {
+	return d.Cidades[d.indiceCidades[d.IBGEEnderecos[codEndereco-1]]]
+}
+
+// promocaoVigente informa se a promoção vale na data informada.
+func (d *Dimensoes) promocaoVigente(codPromocao int, data time.Time) bool {
+	p := d.Promocoes[codPromocao-1]
+	return !data.Before(p.DatInicioVigencia) && data.Before(p.DatFimVigencia)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tamanho das partes sorteadas dos documentos: os 9 dígitos do CPF antes
+// dos verificadores e os 8 da raiz do CNPJ, que identifica a empresa.
+const (
+	moduloCPF  = 1_000_000_000
+	moduloCNPJ = 100_000_000
+
+	// maxFiliais é o limite de lojas da rede: a filial ocupa 4 dígitos no
+	// CNPJ, e a loja de código n é a filial n (a matriz é a 0001).
+	maxFiliais = 9999
+)
+
+// permutacao embaralha os códigos de [0, m) com a bijeção x -> (a*x + b)
+// mod m. Como a é coprimo de m (uma potência de 10), códigos distintos
+// nunca dão o mesmo resultado, o que garante documentos únicos sem
+// precisar lembrar os já gerados.
+type permutacao struct {
+	a, b, m int
+}
+
+func novaPermutacao(cfg *Config, entidade string, m int) permutacao {
+	rng := novoRand(cfg, entidade, 0)
+	finais := []int{1, 3, 7, 9} // coprimos de 10
+	a := rng.Intn(m/10)*10 + finais[rng.Intn(len(finais))]
+	return permutacao{a: a, b: rng.Intn(m), m: m}
+}
+
+func (p permutacao) aplicar(x int) int {
+	return (p.a*x + p.b) % p.m
+}
+
+// documentos gera os CPFs dos clientes e os CNPJs da rede de lojas e dos
+// fornecedores. Os números dependem só da semente e do código da entidade.
+type documentos struct {
+	cpf     permutacao
+	cnpj    permutacao
+	mascara bool
+}
+
+func novosDocumentos(cfg *Config) *documentos {
+	return &documentos{
+		cpf:     novaPermutacao(cfg, "cpf", moduloCPF),
+		cnpj:    novaPermutacao(cfg, "cnpj", moduloCNPJ),
+		mascara: cfg.Documentos.Mascara,
+	}
+}
+
+// cpfCliente devolve o CPF do cliente. Bases com todos os dígitos iguais,
+// que passam no cálculo dos verificadores mas são recusadas pela Receita,
+// são trocadas pela seguinte na permutação (cycle walking), o que mantém a
+// unicidade.
+func (d *documentos) cpfCliente(codCliente int) string {
+	base := d.cpf.aplicar(codCliente)
+	for digitosIguais(base) {
+		base = d.cpf.aplicar(base)
+	}
+
+	digitos := fmt.Sprintf("%09d", base)
+	digitos += digitoVerificador(digitos, 10)
+	digitos += digitoVerificador(digitos, 11)
+	if !d.mascara {
+		return digitos
+	}
+	return digitos[0:3] + "." + digitos[3:6] + "." + digitos[6:9] + "-" + digitos[9:]
+}
+
+// cnpjLoja devolve o CNPJ da loja: todas as lojas têm a raiz da rede, que
+// é a posição 0 da permutação, e a filial é o código da loja.
+func (d *documentos) cnpjLoja(codLoja int) string {
+	return d.formatarCNPJ(d.cnpj.aplicar(0), codLoja)
+}
+
+// cnpjFornecedor devolve o CNPJ da matriz do fornecedor. As raízes vêm das
+// posições 1 em diante, então nunca coincidem com a da rede.
+func (d *documentos) cnpjFornecedor(codFornecedor int) string {
+	return d.formatarCNPJ(d.cnpj.aplicar(codFornecedor), 1)
+}
+
+func (d *documentos) formatarCNPJ(raiz, filial int) string {
+	digitos := fmt.Sprintf("%08d%04d", raiz, filial)
+	digitos += digitoVerificadorCNPJ(digitos)
+	digitos += digitoVerificadorCNPJ(digitos)
+	if !d.mascara {
+		return digitos
+	}
+	return digitos[0:2] + "." + digitos[2:5] + "." + digitos[5:8] + "/" + digitos[8:12] + "-" + digitos[12:]
+}
+
+// digitoVerificador calcula o dígito do CPF (módulo 11), com pesos
+// decrescentes a partir de pesoInicial.
+func digitoVerificador(digitos string, pesoInicial int) string {
+	soma := 0
+	for i, c := range digitos {
+		soma += int(c-'0') * (pesoInicial - i)
+	}
+	return restoModulo11(soma)
+}
+
+// digitoVerificadorCNPJ calcula o dígito do CNPJ (módulo 11), com pesos de
+// 2 a 9 da direita para a esquerda, recomeçando em 2 depois do 9.
+func digitoVerificadorCNPJ(digitos string) string {
+	soma := 0
+	for i := range digitos {
+		peso := 2 + (len(digitos)-1-i)%8
+		soma += int(digitos[i]-'0') * peso
+	}
+	return restoModulo11(soma)
+}
+
+func restoModulo11(soma int) string {
+	resto := soma % 11
+	if resto < 2 {
+		return "0"
+	}
+	return fmt.Sprint(11 - resto)
+}
+
+func digitosIguais(base int) bool {
+	digitos := fmt.Sprintf("%09d", base)
+	return strings.Count(digitos, digitos[:1]) == len(digitos)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestDigitosVerificadoresCPF(t *testing.T) {
+	casos := []struct {
+		base, digitos string
+	}{
+		{"529982247", "25"},
+		{"111444777", "35"},
+		{"123456789", "09"}, // resto < 2 no primeiro dígito
+	}
+	for _, c := range casos {
+		t.Run(c.base, func(t *testing.T) {
+			primeiro := digitoVerificador(c.base, 10)
+			segundo := digitoVerificador(c.base+primeiro, 11)
+			if got := primeiro + segundo; got != c.digitos {
+				t.Errorf("dígitos de %s = %s, esperados %s", c.base, got, c.digitos)
+			}
+		})
+	}
+}
+
+func TestDigitosVerificadoresCNPJ(t *testing.T) {
+	casos := []struct {
+		base, digitos string
+	}{
+		{"112223330001", "81"},
+		{"114447770001", "61"},
+		{"000000000001", "91"}, // Banco do Brasil, 00.000.000/0001-91
+	}
+	for _, c := range casos {
+		t.Run(c.base, func(t *testing.T) {
+			primeiro := digitoVerificadorCNPJ(c.base)
+			segundo := digitoVerificadorCNPJ(c.base + primeiro)
+			if got := primeiro + segundo; got != c.digitos {
+				t.Errorf("dígitos de %s = %s, esperados %s", c.base, got, c.digitos)
+			}
+		})
+	}
+}
+
+func TestPermutacaoSemRepeticao(t *testing.T) {
+	casos := []struct {
+		entidade string
+		semente  int64
+		m        int
+	}{
+		{"cpf", 1, 1_000},
+		{"cpf", 42, 100_000},
+		{"cnpj", 7, 1_000_000},
+		{"cnpj", 20250101, 10},
+	}
+	for _, c := range casos {
+		t.Run(fmt.Sprintf("%s/%d", c.entidade, c.m), func(t *testing.T) {
+			p := novaPermutacao(&Config{Semente: c.semente}, c.entidade, c.m)
+			vistos := make([]bool, c.m)
+			for x := 0; x < c.m; x++ {
+				y := p.aplicar(x)
+				if y < 0 || y >= c.m {
+					t.Fatalf("aplicar(%d) = %d, fora de [0, %d)", x, y, c.m)
+				}
+				if vistos[y] {
+					t.Fatalf("aplicar(%d) = %d repete um valor já produzido", x, y)
+				}
+				vistos[y] = true
+			}
+		})
+	}
+}
+
+func TestDocumentosGerados(t *testing.T) {
+	naoDigito := regexp.MustCompile(`\D`)
+	cpfFormatado := regexp.MustCompile(`^\d{3}\.\d{3}\.\d{3}-\d{2}$`)
+	cnpjFormatado := regexp.MustCompile(`^\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2}$`)
+
+	casos := []struct {
+		nome    string
+		mascara bool
+		cpf     *regexp.Regexp
+		cnpj    *regexp.Regexp
+	}{
+		{"sem máscara", false, regexp.MustCompile(`^\d{11}$`), regexp.MustCompile(`^\d{14}$`)},
+		{"com máscara", true, cpfFormatado, cnpjFormatado},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			cfg := &Config{Semente: 3}
+			cfg.Documentos.Mascara = c.mascara
+			docs := novosDocumentos(cfg)
+
+			vistos := make(map[string]bool)
+			for cod := 1; cod <= 10_000; cod++ {
+				cpf := docs.cpfCliente(cod)
+				if !c.cpf.MatchString(cpf) {
+					t.Fatalf("CPF %q fora do formato", cpf)
+				}
+				digitos := naoDigito.ReplaceAllString(cpf, "")
+				if base, _ := strconv.Atoi(digitos[:9]); digitosIguais(base) {
+					t.Fatalf("CPF %q com todos os dígitos iguais", cpf)
+				}
+				primeiro := digitoVerificador(digitos[:9], 10)
+				if digitos[9:] != primeiro+digitoVerificador(digitos[:9]+primeiro, 11) {
+					t.Fatalf("CPF %q com dígitos verificadores inválidos", cpf)
+				}
+				if vistos[cpf] {
+					t.Fatalf("CPF %q repetido", cpf)
+				}
+				vistos[cpf] = true
+			}
+
+			for cod := 1; cod <= 100; cod++ {
+				for _, cnpj := range []string{docs.cnpjLoja(cod), docs.cnpjFornecedor(cod)} {
+					if !c.cnpj.MatchString(cnpj) {
+						t.Fatalf("CNPJ %q fora do formato", cnpj)
+					}
+					if vistos[cnpj] {
+						t.Fatalf("CNPJ %q repetido", cnpj)
+					}
+					vistos[cnpj] = true
+				}
+			}
+		})
+	}
+}
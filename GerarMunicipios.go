@@ -0,0 +1,103 @@
+//go:build ignore
+
+// GerarMunicipios monta dados/municipios.csv, o cadastro de municípios
+// embutido no gerador, a partir das APIs públicas do IBGE: a lista de
+// municípios (localidades) e a população residente do Censo 2022 (tabela
+// 4714 do SIDRA). Rode com go generate.
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+)
+
+const (
+	urlMunicipios = "https://servicodados.ibge.gov.br/api/v1/localidades/municipios"
+	urlPopulacao  = "https://apisidra.ibge.gov.br/values/t/4714/n6/all/v/93/p/2022"
+	arquivoSaida  = "dados/municipios.csv"
+)
+
+// municipioIBGE é o trecho usado de cada item da API de localidades. A UF
+// vem da região imediata, que todo município tem (a microrregião falta nos
+// criados depois de 2017).
+type municipioIBGE struct {
+	ID             int    `json:"id"`
+	Nome           string `json:"nome"`
+	RegiaoImediata struct {
+		RegiaoIntermediaria struct {
+			UF struct {
+				Sigla string `json:"sigla"`
+			} `json:"UF"`
+		} `json:"regiao-intermediaria"`
+	} `json:"regiao-imediata"`
+}
+
+// valorSIDRA é uma linha da API do SIDRA: D1C é o código do município e V
+// a população. A primeira linha traz os rótulos das colunas.
+type valorSIDRA struct {
+	D1C string `json:"D1C"`
+	V   string `json:"V"`
+}
+
+func main() {
+	var municipios []municipioIBGE
+	if err := lerJSON(urlMunicipios, &municipios); err != nil {
+		log.Fatalf("Erro ao ler os municípios: %v", err)
+	}
+
+	var valores []valorSIDRA
+	if err := lerJSON(urlPopulacao, &valores); err != nil {
+		log.Fatalf("Erro ao ler a população: %v", err)
+	}
+	populacao := make(map[int]int, len(valores))
+	for _, v := range valores {
+		codigo, errCod := strconv.Atoi(v.D1C)
+		habitantes, errHab := strconv.Atoi(v.V)
+		if errCod == nil && errHab == nil {
+			populacao[codigo] = habitantes
+		}
+	}
+
+	slices.SortFunc(municipios, func(a, b municipioIBGE) int { return cmp.Compare(a.ID, b.ID) })
+
+	f, err := os.Create(arquivoSaida)
+	if err != nil {
+		log.Fatalf("Erro ao criar %s: %v", arquivoSaida, err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"cod_ibge", "nome", "uf", "populacao"})
+	for _, m := range municipios {
+		habitantes, ok := populacao[m.ID]
+		if !ok {
+			log.Fatalf("Município %d (%s) sem população no Censo 2022", m.ID, m.Nome)
+		}
+		w.Write([]string{strconv.Itoa(m.ID), m.Nome, m.RegiaoImediata.RegiaoIntermediaria.UF.Sigla, strconv.Itoa(habitantes)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatalf("Erro ao gravar %s: %v", arquivoSaida, err)
+	}
+	fmt.Printf("%d municípios gravados em %s\n", len(municipios), arquivoSaida)
+}
+
+func lerJSON(url string, destino any) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(destino)
+}
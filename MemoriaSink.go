@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoriaSink guarda os registros gerados em memória, para que o mesmo
+// conjunto de dados possa ser gravado depois em cada banco separadamente.
+type MemoriaSink struct {
+	mu        sync.Mutex
+	ordem     []string // tabelas na ordem em que apareceram
+	registros map[string][]Registro
+}
+
+func novoMemoriaSink() *MemoriaSink {
+	return &MemoriaSink{registros: make(map[string][]Registro)}
+}
+
+func (s *MemoriaSink) Nome() string { return "memória" }
+
+func (s *MemoriaSink) Abrir(ctx context.Context) error { return nil }
+
+// Escrever copia os registros, já que o Lote reaproveita o slice recebido.
+func (s *MemoriaSink) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existentes, ok := s.registros[tabela]
+	if !ok {
+		s.ordem = append(s.ordem, tabela)
+	}
+	s.registros[tabela] = append(existentes, registros...)
+	return nil
+}
+
+func (s *MemoriaSink) Flush(ctx context.Context) error { return nil }
+
+func (s *MemoriaSink) Fechar(ctx context.Context) error { return nil }
+
+// tabelas devolve as tabelas recebidas, das referenciadas para as que as
+// referenciam (a ordem de geração).
+func (s *MemoriaSink) tabelas() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.ordem)
+}
+
+// total devolve quantos registros de todas as tabelas estão guardados.
+func (s *MemoriaSink) total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	for _, registros := range s.registros {
+		total += len(registros)
+	}
+	return total
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// chaveMetadados identifica a única linha de metadados de um destino.
+const chaveMetadados = "geracao"
+
+// Metadados registra, em cada destino, de que geração vieram os dados. O
+// bench lê esta linha do banco que mede em vez de confiar na própria
+// configuração, que pode não ter a semente ou os volumes usados na geração.
+// A linha guarda também a numeração das notas, de onde a simulação continua
+// sem percorrer nota_fiscal; a simulação a atualiza ao terminar.
+type Metadados struct {
+	Chave          string  `bson:"chave" chave:"particao"`
+	Semente        int64   `bson:"semente"`
+	Escala         float64 `bson:"escala"`
+	DataReferencia string  `bson:"data_referencia"`
+	Volumes        string  `bson:"volumes"` // Volumes em JSON
+	MaiorSeqNota   int     `bson:"maior_seq_nota"`
+	MaioresNumNota string  `bson:"maiores_num_nota"` // cod_pdv -> maior num_nota, em JSON
+}
+
+func (Metadados) Tabela() string { return "metadados" }
+
+// gerarMetadados grava os metadados da geração depois de todas as outras
+// tabelas, então um destino com a geração interrompida fica sem eles.
+func gerarMetadados(ctx context.Context, cfg *Config, saida *Saida, maioresNumNota map[int]float64) {
+	volumes, _ := json.Marshal(cfg.Volumes) // só inteiros, não falha
+
+	lote := saida.novoLote(ctx)
+	lote.Adicionar(Metadados{
+		Chave:          chaveMetadados,
+		Semente:        cfg.Semente,
+		Escala:         cfg.Escala,
+		DataReferencia: cfg.DataReferencia,
+		Volumes:        string(volumes),
+		MaiorSeqNota:   cfg.Volumes.NotasFiscais,
+		MaioresNumNota: numeracaoJSON(maioresNumNota),
+	})
+	lote.Descarregar()
+}
+
+// ConjuntoDados identifica o conjunto de dados medido por um bench.
+type ConjuntoDados struct {
+	Semente        int64   `json:"semente"`
+	Escala         float64 `json:"escala"`
+	DataReferencia string  `json:"data_referencia"`
+	Volumes        Volumes `json:"volumes"`
+}
+
+func (m Metadados) conjunto() (ConjuntoDados, error) {
+	c := ConjuntoDados{Semente: m.Semente, Escala: m.Escala, DataReferencia: m.DataReferencia}
+	if err := json.Unmarshal([]byte(m.Volumes), &c.Volumes); err != nil {
+		return ConjuntoDados{}, fmt.Errorf("volumes inválidos nos metadados: %w", err)
+	}
+	return c, nil
+}
+
+// numeracaoJSON serializa o maior num_nota de cada PDV para os metadados.
+func numeracaoJSON(maioresNumNota map[int]float64) string {
+	dados, _ := json.Marshal(maioresNumNota) // chaves inteiras, não falha
+	return string(dados)
+}
+
+// maioresNumNota interpreta a numeração das notas gravada nos metadados.
+func (m Metadados) maioresNumNota() (map[int]float64, error) {
+	maiores := make(map[int]float64)
+	if err := json.Unmarshal([]byte(m.MaioresNumNota), &maiores); err != nil {
+		return nil, fmt.Errorf("numeração das notas inválida nos metadados: %w", err)
+	}
+	return maiores, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MongoArquivoSink grava as coleções do MongoDB em arquivos, para carregá-las
+// depois sem passar pelo driver:
+//
+//   - jsonl: <coleção>.json em JSON Lines, no Extended JSON v2 relaxado
+//     (datas como {"$date": ...}), lido pelo mongoimport;
+//   - bson: <database>/<coleção>.bson e <coleção>.metadata.json no layout
+//     do mongodump, lido pelo mongorestore (com --gzip se Arquivos.Gzip).
+//
+// O .metadata.json leva o validador e os índices de schema create, para que
+// o mongorestore recrie a coleção como o comando schema a criaria.
+type MongoArquivoSink struct {
+	cfg     *Config
+	formato string // sinkJSONL ou sinkBSON
+
+	mu       sync.Mutex
+	arquivos map[string]*arquivoMongo
+}
+
+// arquivoMongo é o arquivo de uma coleção, com sua própria trava.
+type arquivoMongo struct {
+	mu      sync.Mutex
+	arquivo *arquivoSaida
+	w       *bufio.Writer
+}
+
+func novoMongoArquivoSink(cfg *Config, formato string) *MongoArquivoSink {
+	return &MongoArquivoSink{cfg: cfg, formato: formato, arquivos: make(map[string]*arquivoMongo)}
+}
+
+func (s *MongoArquivoSink) Nome() string {
+	if s.formato == sinkBSON {
+		return "BSON"
+	}
+	return "JSON Lines"
+}
+
+func (s *MongoArquivoSink) Abrir(ctx context.Context) error { return nil }
+
+// Aceita descarta as tabelas de consulta do Cassandra, como o MongoSink.
+func (s *MongoArquivoSink) Aceita(r Registro) bool {
+	_, consulta := r.(TabelaConsulta)
+	return !consulta
+}
+
+func (s *MongoArquivoSink) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	if !s.Aceita(registros[0]) {
+		return nil
+	}
+
+	a, err := s.arquivo(registros[0])
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	falha := &ErroLote{Tabela: tabela, Total: len(registros)}
+	for _, r := range registros {
+		doc, err := s.codificar(r)
+		if err != nil {
+			falha.Falhas = append(falha.Falhas, FalhaRegistro{Chave: chaveDe(r), Err: err})
+			continue
+		}
+		if _, err := a.w.Write(doc); err != nil {
+			return err
+		}
+	}
+
+	if len(falha.Falhas) > 0 {
+		return falha
+	}
+	return nil
+}
+
+// codificar serializa um documento: BSON puro no formato bson, uma linha
+// de Extended JSON no formato jsonl.
+func (s *MongoArquivoSink) codificar(r Registro) ([]byte, error) {
+	if s.formato == sinkBSON {
+		return bson.Marshal(r)
+	}
+	doc, err := bson.MarshalExtJSON(r, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(doc, '\n'), nil
+}
+
+// arquivo devolve o arquivo da coleção do registro, criando-o no primeiro
+// uso. No formato bson, os metadados são gravados junto.
+func (s *MongoArquivoSink) arquivo(modelo Registro) (*arquivoMongo, error) {
+	tabela := modelo.Tabela()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.arquivos[tabela]; ok {
+		return a, nil
+	}
+
+	nome := tabela + ".json"
+	if s.formato == sinkBSON {
+		if err := s.escreverMetadados(modelo); err != nil {
+			return nil, err
+		}
+		nome = filepath.Join(s.cfg.Mongo.Database, tabela+".bson")
+	}
+
+	arquivo, err := criarArquivo(s.cfg, nome)
+	if err != nil {
+		return nil, err
+	}
+	a := &arquivoMongo{arquivo: arquivo, w: bufio.NewWriter(arquivo)}
+
+	s.arquivos[tabela] = a
+	return a, nil
+}
+
+// escreverMetadados grava o <coleção>.metadata.json da coleção do registro.
+func (s *MongoArquivoSink) escreverMetadados(modelo Registro) error {
+	tabela := modelo.Tabela()
+	meta, err := bson.MarshalExtJSON(metadadosMongo(modelo), false, false)
+	if err != nil {
+		return err
+	}
+
+	arquivo, err := criarArquivo(s.cfg, filepath.Join(s.cfg.Mongo.Database, tabela+".metadata.json"))
+	if err != nil {
+		return err
+	}
+	if _, err := arquivo.Write(meta); err != nil {
+		arquivo.Close()
+		return err
+	}
+	return arquivo.Close()
+}
+
+// metadadosMongo monta o documento de metadados do mongodump para a coleção
+// do registro: opções de criação (validador) e índices.
+func metadadosMongo(modelo Registro) bson.D {
+	indices := bson.A{bson.D{
+		{Key: "v", Value: 2},
+		{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}},
+		{Key: "name", Value: "_id_"},
+	}}
+	for _, indice := range indicesMongoDe(modelo) {
+		spec := bson.D{
+			{Key: "v", Value: 2},
+			{Key: "key", Value: indice.chaves()},
+			{Key: "name", Value: indice.nome()},
+		}
+		if indice.unico {
+			spec = append(spec, bson.E{Key: "unique", Value: true})
+		}
+		indices = append(indices, spec)
+	}
+
+	return bson.D{
+		{Key: "options", Value: bson.D{{Key: "validator", Value: validadorMongo(modelo)}}},
+		{Key: "indexes", Value: indices},
+		{Key: "collectionName", Value: modelo.Tabela()},
+		{Key: "type", Value: "collection"},
+	}
+}
+
+func (s *MongoArquivoSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var erros []error
+	for _, a := range s.arquivos {
+		a.mu.Lock()
+		erros = append(erros, a.w.Flush())
+		a.mu.Unlock()
+	}
+	return errors.Join(erros...)
+}
+
+func (s *MongoArquivoSink) Fechar(ctx context.Context) error {
+	err := s.Flush(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	erros := []error{err}
+	for tabela, a := range s.arquivos {
+		erros = append(erros, a.arquivo.Close())
+		delete(s.arquivos, tabela)
+	}
+	return errors.Join(erros...)
+}
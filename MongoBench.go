@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pipelinesMongo são as agregações do README, na mesma ordem de consultas.
+var pipelinesMongo = map[int]struct {
+	colecao  string
+	pipeline mongo.Pipeline
+}{
+	1: {"nota_fiscal", mongo.Pipeline{
+		{{Key: "$project", Value: bson.D{
+			{Key: "year", Value: bson.D{{Key: "$year", Value: bson.D{
+				{Key: "date", Value: "$dat_nota"},
+				{Key: "timezone", Value: fmt.Sprintf("%+03d:00", horasFusoLojas)},
+			}}}},
+			{Key: "vlr_nota", Value: 1},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$year"},
+			{Key: "total_vendido", Value: bson.D{{Key: "$sum", Value: "$vlr_nota"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}},
+	2: {"item_nota_fiscal", mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$cod_produto"},
+			{Key: "quantidade_total", Value: bson.D{{Key: "$sum", Value: "$qtd_produto"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "quantidade_total", Value: -1}}}},
+		{{Key: "$limit", Value: 5}},
+		lookupMongo("produto", "_id", "cod_produto", "produto_info"),
+		{{Key: "$project", Value: bson.D{
+			{Key: "nom_produto", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$produto_info.nom_produto", 0}}}},
+			{Key: "quantidade_total", Value: 1},
+		}}},
+	}},
+	3: {"nota_fiscal", mongo.Pipeline{
+		lookupMongo("cliente", "cod_cliente", "cod_cliente", "cliente"),
+		lookupMongo("endereco", "cliente.cod_endereco", "cod_endereco", "endereco"),
+		lookupMongo("cidade", "endereco.cod_ibge", "cod_ibge", "cidade"),
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$cidade.nom_estado"},
+			{Key: "total_faturado", Value: bson.D{{Key: "$sum", Value: "$vlr_nota"}}},
+		}}},
+	}},
+	4: {"cliente", mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "flg_fidelizado", Value: "S"}}}},
+		lookupMongo("endereco", "cod_endereco", "cod_endereco", "endereco"),
+		lookupMongo("cidade", "endereco.cod_ibge", "cod_ibge", "cidade"),
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$cidade.nom_cidade"},
+			{Key: "qtd_fidelizados", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}},
+	5: {"item_nota_fiscal", mongo.Pipeline{
+		lookupMongo("produto", "cod_produto", "cod_produto", "produto"),
+		lookupMongo("setor", "produto.cod_setor", "cod_setor", "setor"),
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$setor.nom_setor"},
+			{Key: "lucro_medio", Value: bson.D{{Key: "$avg", Value: bson.D{
+				{Key: "$subtract", Value: bson.A{"$vlr_venda", "$vlr_custo"}},
+			}}}},
+		}}},
+	}},
+}
+
+// lookupMongo monta um estágio $lookup por igualdade de campos.
+func lookupMongo(from, localField, foreignField, as string) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.D{
+		{Key: "from", Value: from},
+		{Key: "localField", Value: localField},
+		{Key: "foreignField", Value: foreignField},
+		{Key: "as", Value: as},
+	}}}
+}
+
+// executorMongo roda as consultas como pipelines de agregação.
+type executorMongo struct {
+	cfg    *Config
+	client *mongo.Client
+	db     *mongo.Database
+	cpf    string // procurado na consulta 6
+}
+
+func (e *executorMongo) Nome() string { return "MongoDB" }
+
+func (e *executorMongo) Abrir(ctx context.Context) error {
+	client, err := conectarMongoDB(e.cfg)
+	if err != nil {
+		return err
+	}
+	e.client = client
+	e.db = client.Database(e.cfg.Mongo.Database)
+	return nil
+}
+
+func (e *executorMongo) Versao(ctx context.Context) (string, error) {
+	var info struct {
+		Version string `bson:"version"`
+	}
+	err := e.db.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info)
+	return info.Version, err
+}
+
+func (e *executorMongo) Metadados(ctx context.Context) (Metadados, error) {
+	var metadados Metadados
+	err := e.db.Collection(Metadados{}.Tabela()).
+		FindOne(ctx, bson.D{{Key: "chave", Value: chaveMetadados}}).
+		Decode(&metadados)
+	return metadados, err
+}
+
+func (e *executorMongo) Preparar(ctx context.Context, c Consulta) error {
+	if c.Numero != consultaClientePorCPF {
+		return nil
+	}
+	var cliente Cliente
+	err := e.db.Collection(cliente.Tabela()).
+		FindOne(ctx, bson.D{{Key: "cod_cliente", Value: codClienteConsulta}}).
+		Decode(&cliente)
+	e.cpf = cliente.NumCPF
+	return err
+}
+
+func (e *executorMongo) Executar(ctx context.Context, c Consulta) (int, error) {
+	if c.Numero == consultaClientePorCPF {
+		return e.clientePorCPF(ctx)
+	}
+
+	consulta, ok := pipelinesMongo[c.Numero]
+	if !ok {
+		return 0, fmt.Errorf("consulta %d sem pipeline no MongoDB", c.Numero)
+	}
+
+	cursor, err := e.db.Collection(consulta.colecao).Aggregate(ctx, consulta.pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	linhas := 0
+	for cursor.Next(ctx) {
+		linhas++
+	}
+	return linhas, cursor.Err()
+}
+
+// clientePorCPF busca o cliente pelo índice de num_cpf, sem agregação.
+func (e *executorMongo) clientePorCPF(ctx context.Context) (int, error) {
+	filtro := bson.D{{Key: "num_cpf", Value: e.cpf}}
+	cursor, err := e.db.Collection(Cliente{}.Tabela()).Find(ctx, filtro)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	linhas := 0
+	for cursor.Next(ctx) {
+		linhas++
+	}
+	return linhas, cursor.Err()
+}
+
+func (e *executorMongo) Fechar(ctx context.Context) error {
+	if e.client == nil {
+		return nil
+	}
+	return e.client.Disconnect(ctx)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// oltpMongo executa as operações de caixa com comandos de um documento.
+type oltpMongo struct {
+	cfg    *Config
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+func (o *oltpMongo) Nome() string { return "MongoDB" }
+
+func (o *oltpMongo) Abrir(ctx context.Context) error {
+	client, err := conectarMongoDB(o.cfg)
+	if err != nil {
+		return err
+	}
+	o.client = client
+	o.db = client.Database(o.cfg.Mongo.Database)
+	return nil
+}
+
+func (o *oltpMongo) Metadados(ctx context.Context) (Metadados, error) {
+	var metadados Metadados
+	err := o.db.Collection(metadados.Tabela()).
+		FindOne(ctx, bson.D{{Key: "chave", Value: chaveMetadados}}).
+		Decode(&metadados)
+	return metadados, err
+}
+
+func (o *oltpMongo) GravarNumeracao(ctx context.Context, maiorSeqNota int, maioresNumNota string) error {
+	_, err := o.db.Collection(Metadados{}.Tabela()).UpdateOne(ctx,
+		bson.D{{Key: "chave", Value: chaveMetadados}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "maior_seq_nota", Value: maiorSeqNota},
+			{Key: "maiores_num_nota", Value: maioresNumNota},
+		}}})
+	return err
+}
+
+func (o *oltpMongo) AbrirNota(ctx context.Context, nota NotaFiscal) error {
+	_, err := o.db.Collection(nota.Tabela()).InsertOne(ctx, nota)
+	return err
+}
+
+func (o *oltpMongo) LerProduto(ctx context.Context, codProduto int) (Produto, error) {
+	var produto Produto
+	err := o.db.Collection(Produto{}.Tabela()).
+		FindOne(ctx, bson.D{{Key: "cod_produto", Value: codProduto}}).
+		Decode(&produto)
+	return produto, err
+}
+
+func (o *oltpMongo) AdicionarItem(ctx context.Context, item ItemNotaFiscal) error {
+	_, err := o.db.Collection(item.Tabela()).InsertOne(ctx, item)
+	return err
+}
+
+func (o *oltpMongo) BuscarCliente(ctx context.Context, codCliente int) (Cliente, error) {
+	var cliente Cliente
+	err := o.db.Collection(Cliente{}.Tabela()).
+		FindOne(ctx, bson.D{{Key: "cod_cliente", Value: codCliente}}).
+		Decode(&cliente)
+	return cliente, err
+}
+
+// FecharNota grava o total da nota, pago em cartão.
+func (o *oltpMongo) FecharNota(ctx context.Context, seqNota int, vlrNota float64) error {
+	_, err := o.db.Collection(NotaFiscal{}.Tabela()).UpdateOne(ctx,
+		bson.D{{Key: "seq_nota", Value: seqNota}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "vlr_nota", Value: vlrNota},
+			{Key: "vlr_cartao", Value: vlrNota},
+		}}},
+	)
+	return err
+}
+
+func (o *oltpMongo) Fechar(ctx context.Context) error {
+	if o.client == nil {
+		return nil
+	}
+	return o.client.Disconnect(ctx)
+}
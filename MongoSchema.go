@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indicesConsultaMongo são os índices usados pelas consultas do README,
+// além do índice único da chave de cada coleção.
+var indicesConsultaMongo = map[string][][]string{
+	"item_nota_fiscal": {{"cod_produto"}},
+	"nota_fiscal":      {{"dat_nota"}, {"cod_cliente"}},
+	"cliente":          {{"flg_fidelizado"}, {"num_cpf"}},
+}
+
+// indiceMongo é um índice esperado numa coleção.
+type indiceMongo struct {
+	campos []string
+	unico  bool
+}
+
+// nome segue a convenção do servidor para índices sem nome explícito.
+func (i indiceMongo) nome() string {
+	partes := make([]string, len(i.campos))
+	for j, campo := range i.campos {
+		partes[j] = campo + "_1"
+	}
+	return strings.Join(partes, "_")
+}
+
+// chaves devolve o documento de chaves do índice, todas ascendentes.
+func (i indiceMongo) chaves() bson.D {
+	chaves := bson.D{}
+	for _, campo := range i.campos {
+		chaves = append(chaves, bson.E{Key: campo, Value: 1})
+	}
+	return chaves
+}
+
+// indicesMongoDe devolve os índices esperados para a coleção do registro:
+// a chave (tag chave) como índice único e os índices de consulta.
+func indicesMongoDe(r Registro) []indiceMongo {
+	chave := indiceMongo{unico: true}
+	var clustering []string
+	for _, c := range camposDe(reflect.TypeOf(r)) {
+		switch c.chave {
+		case chaveParticao:
+			chave.campos = append(chave.campos, c.coluna)
+		case chaveClustering:
+			clustering = append(clustering, c.coluna)
+		}
+	}
+	chave.campos = append(chave.campos, clustering...)
+
+	indices := []indiceMongo{chave}
+	for _, campos := range indicesConsultaMongo[r.Tabela()] {
+		indices = append(indices, indiceMongo{campos: campos})
+	}
+	return indices
+}
+
+// validadorMongo deriva o $jsonSchema da coleção a partir das tags bson.
+// Campos com omitempty são opcionais; os demais são obrigatórios.
+func validadorMongo(r Registro) bson.D {
+	t := reflect.TypeOf(r)
+	propriedades := bson.D{}
+	obrigatorios := bson.A{}
+	for _, c := range camposDe(t) {
+		tipo := bson.D{{Key: "bsonType", Value: tipoBSON(t.Field(c.indice).Type)}}
+		propriedades = append(propriedades, bson.E{Key: c.coluna, Value: tipo})
+		if !c.opcional {
+			obrigatorios = append(obrigatorios, c.coluna)
+		}
+	}
+
+	return bson.D{{Key: "$jsonSchema", Value: bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: obrigatorios},
+		{Key: "properties", Value: propriedades},
+	}}}
+}
+
+// tipoBSON converte o tipo Go de um campo no bsonType gravado pelo driver.
+// O driver grava int como int32 quando o valor cabe e como int64 senão.
+func tipoBSON(t reflect.Type) any {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "date"
+	}
+	switch t.Kind() {
+	case reflect.Int:
+		return bson.A{"int", "long"}
+	case reflect.Int32:
+		return "int"
+	case reflect.Int64:
+		return "long"
+	case reflect.Float64:
+		return "double"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	}
+	panic(fmt.Sprintf("tipo sem equivalente BSON: %s", t))
+}
+
+// esquemaMongo cria as coleções com validador e os índices das consultas.
+type esquemaMongo struct {
+	cfg *Config
+}
+
+func (e *esquemaMongo) Nome() string { return "MongoDB" }
+
+// Criar cria as coleções que faltam e atualiza o validador das existentes.
+// Com Mongo.IndicesAposCarga, os índices ficam para o fim de gerar ou load.
+func (e *esquemaMongo) Criar(ctx context.Context) error {
+	client, err := conectarMongoDB(e.cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+	db := client.Database(e.cfg.Mongo.Database)
+
+	existentes, err := db.ListCollectionNames(ctx, bson.D{})
+	if err != nil {
+		return err
+	}
+
+	for _, modelo := range tabelas {
+		nome := modelo.Tabela()
+		validador := validadorMongo(modelo)
+
+		if slices.Contains(existentes, nome) {
+			err = db.RunCommand(ctx, bson.D{
+				{Key: "collMod", Value: nome},
+				{Key: "validator", Value: validador},
+			}).Err()
+		} else {
+			err = db.CreateCollection(ctx, nome, options.CreateCollection().SetValidator(validador))
+		}
+		if err != nil {
+			return fmt.Errorf("coleção %s: %w", nome, err)
+		}
+	}
+
+	if e.cfg.Mongo.IndicesAposCarga {
+		fmt.Println("Índices do MongoDB serão criados após a carga")
+		return nil
+	}
+	return criarIndicesMongo(ctx, db)
+}
+
+func (e *esquemaMongo) Remover(ctx context.Context) error {
+	client, err := conectarMongoDB(e.cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+	db := client.Database(e.cfg.Mongo.Database)
+
+	for _, modelo := range tabelas {
+		if err := db.Collection(modelo.Tabela()).Drop(ctx); err != nil {
+			return fmt.Errorf("coleção %s: %w", modelo.Tabela(), err)
+		}
+	}
+	return nil
+}
+
+func (e *esquemaMongo) Verificar(ctx context.Context) ([]string, error) {
+	client, err := conectarMongoDB(e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Disconnect(ctx)
+	db := client.Database(e.cfg.Mongo.Database)
+
+	var divergencias []string
+	for _, modelo := range tabelas {
+		nome := modelo.Tabela()
+		specs, err := db.ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: nome}})
+		if err != nil {
+			return nil, err
+		}
+		if len(specs) == 0 {
+			divergencias = append(divergencias, fmt.Sprintf("coleção %s não existe", nome))
+			continue
+		}
+
+		esperado, err := bson.Marshal(validadorMongo(modelo))
+		if err != nil {
+			return nil, err
+		}
+		atual, ok := specs[0].Options.Lookup("validator").DocumentOK()
+		if !ok || !bytes.Equal(atual, esperado) {
+			divergencias = append(divergencias, fmt.Sprintf("coleção %s tem validador diferente do esperado", nome))
+		}
+
+		indices, err := db.Collection(nome).Indexes().ListSpecifications(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, indice := range indicesMongoDe(modelo) {
+			existe := slices.ContainsFunc(indices, func(i *mongo.IndexSpecification) bool {
+				return i.Name == indice.nome()
+			})
+			if !existe {
+				divergencias = append(divergencias, fmt.Sprintf("coleção %s sem o índice %s", nome, indice.nome()))
+			}
+		}
+	}
+	return divergencias, nil
+}
+
+// criarIndices cria os índices numa conexão própria; é usado quando eles
+// foram adiados para depois da carga.
+func (e *esquemaMongo) criarIndices(ctx context.Context) error {
+	client, err := conectarMongoDB(e.cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+
+	return criarIndicesMongo(ctx, client.Database(e.cfg.Mongo.Database))
+}
+
+// criarIndicesMongo cria os índices de todas as coleções geradas.
+func criarIndicesMongo(ctx context.Context, db *mongo.Database) error {
+	var erros []error
+	for _, modelo := range tabelas {
+		var modelos []mongo.IndexModel
+		for _, indice := range indicesMongoDe(modelo) {
+			modelos = append(modelos, mongo.IndexModel{
+				Keys:    indice.chaves(),
+				Options: options.Index().SetName(indice.nome()).SetUnique(indice.unico),
+			})
+		}
+
+		if _, err := db.Collection(modelo.Tabela()).Indexes().CreateMany(ctx, modelos); err != nil {
+			erros = append(erros, fmt.Errorf("índices de %s: %w", modelo.Tabela(), err))
+		}
+	}
+	return errors.Join(erros...)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Modos de escrita do MongoSink
+const (
+	modoInsertMany = "insertmany"
+	modoBulkWrite  = "bulkwrite"
+)
+
+// MongoSink grava cada tabela na coleção de mesmo nome do database
+// configurado, em lotes não ordenados de até Mongo.TamanhoLote documentos.
+type MongoSink struct {
+	cfg    *Config
+	client *mongo.Client
+	db     *mongo.Database
+}
+
+func novoMongoSink(cfg *Config) *MongoSink {
+	return &MongoSink{cfg: cfg}
+}
+
+func (s *MongoSink) Nome() string { return "MongoDB" }
+
+func (s *MongoSink) Abrir(ctx context.Context) error {
+	client, err := conectarMongoDB(s.cfg)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	s.db = client.Database(s.cfg.Mongo.Database)
+	return nil
+}
+
+// Aceita descarta as tabelas de consulta do Cassandra: as consultas do
+// MongoDB usam $lookup sobre as coleções normalizadas.
+func (s *MongoSink) Aceita(r Registro) bool {
+	_, consulta := r.(TabelaConsulta)
+	return !consulta
+}
+
+func (s *MongoSink) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	if !s.Aceita(registros[0]) {
+		return nil
+	}
+
+	collection := s.db.Collection(tabela)
+	falha := &ErroLote{Tabela: tabela, Total: len(registros)}
+
+	for inicio := 0; inicio < len(registros); inicio += s.cfg.Mongo.TamanhoLote {
+		lote := registros[inicio:min(inicio+s.cfg.Mongo.TamanhoLote, len(registros))]
+
+		var err error
+		if s.cfg.Mongo.Modo == modoBulkWrite {
+			err = s.bulkWrite(ctx, collection, lote)
+		} else {
+			err = s.insertMany(ctx, collection, lote)
+		}
+		falha.Falhas = append(falha.Falhas, falhasMongo(lote, err)...)
+	}
+
+	if len(falha.Falhas) > 0 {
+		return falha
+	}
+	return nil
+}
+
+func (s *MongoSink) insertMany(ctx context.Context, collection *mongo.Collection, lote []Registro) error {
+	docs := make([]any, len(lote))
+	for i, r := range lote {
+		docs[i] = r
+	}
+	_, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	return err
+}
+
+func (s *MongoSink) bulkWrite(ctx context.Context, collection *mongo.Collection, lote []Registro) error {
+	modelos := make([]mongo.WriteModel, len(lote))
+	for i, r := range lote {
+		modelos[i] = mongo.NewInsertOneModel().SetDocument(r)
+	}
+	_, err := collection.BulkWrite(ctx, modelos, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// falhasMongo traduz o erro de um InsertMany/BulkWrite nos documentos do
+// lote que falharam. Erros que não apontam documentos (rede, write concern)
+// são atribuídos ao lote inteiro.
+func falhasMongo(lote []Registro, err error) []FalhaRegistro {
+	if err == nil {
+		return nil
+	}
+
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) && len(bwe.WriteErrors) > 0 && bwe.WriteConcernError == nil {
+		falhas := make([]FalhaRegistro, 0, len(bwe.WriteErrors))
+		for _, we := range bwe.WriteErrors {
+			falhas = append(falhas, FalhaRegistro{
+				Chave: chaveDe(lote[we.Index]),
+				Err:   fmt.Errorf("código %d: %s", we.Code, we.Message),
+			})
+		}
+		return falhas
+	}
+
+	falhas := make([]FalhaRegistro, len(lote))
+	for i, r := range lote {
+		falhas[i] = FalhaRegistro{Chave: chaveDe(r), Err: err}
+	}
+	return falhas
+}
+
+func (s *MongoSink) Flush(ctx context.Context) error { return nil }
+
+func (s *MongoSink) Fechar(ctx context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Disconnect(ctx)
+}
+
+// Funções de conexão com bancos de dados
+func conectarMongoDB(cfg *Config) (*mongo.Client, error) {
+	clientOptions := options.Client().ApplyURI(cfg.Mongo.URI)
+	client, err := mongo.Connect(context.Background(), clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.Ping(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Conectado ao MongoDB com sucesso!")
+	return client, nil
+}
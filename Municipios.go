@@ -0,0 +1,129 @@
+package main
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// municipio é uma linha do cadastro de referência de municípios.
+type municipio struct {
+	codIBGE   int
+	nome      string
+	uf        string
+	populacao int // habitantes no Censo 2022
+}
+
+// municipiosCSV é o cadastro de municípios do IBGE (código, nome, UF e
+// população), em ordem de código. Para atualizá-lo a partir das APIs do
+// IBGE, rode go generate.
+//
+//go:generate go run GerarMunicipios.go
+//go:embed dados/municipios.csv
+var municipiosCSV string
+
+var municipios = lerMunicipios(municipiosCSV)
+
+// lerMunicipios interpreta o cadastro embutido. Um cadastro inválido é erro
+// de quem o gerou, então interrompe o programa.
+func lerMunicipios(dados string) []municipio {
+	linhas, err := csv.NewReader(strings.NewReader(dados)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("cadastro de municípios: %v", err))
+	}
+
+	lista := make([]municipio, 0, len(linhas))
+	for _, linha := range linhas[1:] { // a primeira linha é o cabeçalho
+		codIBGE, errCod := strconv.Atoi(linha[0])
+		populacao, errPop := strconv.Atoi(linha[3])
+		if _, ok := unidadesFederativas[linha[2]]; errCod != nil || errPop != nil || populacao <= 0 || !ok {
+			panic(fmt.Sprintf("cadastro de municípios: linha inválida %q", linha))
+		}
+		lista = append(lista, municipio{codIBGE: codIBGE, nome: linha[1], uf: linha[2], populacao: populacao})
+	}
+	return lista
+}
+
+// amostrarMunicipios sorteia n municípios distintos do cadastro, cada um
+// com chance proporcional à população (método de Efraimidis e Spirakis), e
+// os devolve em ordem de código.
+func amostrarMunicipios(rng *rand.Rand, n int) []municipio {
+	type candidato struct {
+		chave float64
+		m     municipio
+	}
+	candidatos := make([]candidato, len(municipios))
+	for i, m := range municipios {
+		candidatos[i] = candidato{math.Log(rng.Float64()) / float64(m.populacao), m}
+	}
+	slices.SortFunc(candidatos, func(a, b candidato) int { return cmp.Compare(b.chave, a.chave) })
+
+	amostra := make([]municipio, n)
+	for i, c := range candidatos[:n] {
+		amostra[i] = c.m
+	}
+	slices.SortFunc(amostra, func(a, b municipio) int { return cmp.Compare(a.codIBGE, b.codIBGE) })
+	return amostra
+}
+
+// unidadeFederativa traz a região de uma UF e as faixas de CEP dos
+// Correios atribuídas a ela (início e fim, inclusive).
+type unidadeFederativa struct {
+	regiao string
+	faixas [][2]int
+}
+
+var unidadesFederativas = map[string]unidadeFederativa{
+	"SP": {"Sudeste", [][2]int{{1000000, 19999999}}},
+	"RJ": {"Sudeste", [][2]int{{20000000, 28999999}}},
+	"ES": {"Sudeste", [][2]int{{29000000, 29999999}}},
+	"MG": {"Sudeste", [][2]int{{30000000, 39999999}}},
+	"BA": {"Nordeste", [][2]int{{40000000, 48999999}}},
+	"SE": {"Nordeste", [][2]int{{49000000, 49999999}}},
+	"PE": {"Nordeste", [][2]int{{50000000, 56999999}}},
+	"AL": {"Nordeste", [][2]int{{57000000, 57999999}}},
+	"PB": {"Nordeste", [][2]int{{58000000, 58999999}}},
+	"RN": {"Nordeste", [][2]int{{59000000, 59999999}}},
+	"CE": {"Nordeste", [][2]int{{60000000, 63999999}}},
+	"PI": {"Nordeste", [][2]int{{64000000, 64999999}}},
+	"MA": {"Nordeste", [][2]int{{65000000, 65999999}}},
+	"PA": {"Norte", [][2]int{{66000000, 68899999}}},
+	"AP": {"Norte", [][2]int{{68900000, 68999999}}},
+	"AM": {"Norte", [][2]int{{69000000, 69299999}, {69400000, 69899999}}},
+	"RR": {"Norte", [][2]int{{69300000, 69399999}}},
+	"AC": {"Norte", [][2]int{{69900000, 69999999}}},
+	"RO": {"Norte", [][2]int{{76800000, 76999999}}},
+	"TO": {"Norte", [][2]int{{77000000, 77999999}}},
+	"DF": {"Centro-Oeste", [][2]int{{70000000, 72799999}, {73000000, 73699999}}},
+	"GO": {"Centro-Oeste", [][2]int{{72800000, 72999999}, {73700000, 76799999}}},
+	"MT": {"Centro-Oeste", [][2]int{{78000000, 78899999}}},
+	"MS": {"Centro-Oeste", [][2]int{{79000000, 79999999}}},
+	"PR": {"Sul", [][2]int{{80000000, 87999999}}},
+	"SC": {"Sul", [][2]int{{88000000, 89999999}}},
+	"RS": {"Sul", [][2]int{{90000000, 99999999}}},
+}
+
+// sortearCEP escolhe um CEP dentro das faixas da UF, proporcionalmente ao
+// tamanho de cada faixa.
+func sortearCEP(rng *rand.Rand, uf string) int {
+	faixas := unidadesFederativas[uf].faixas
+	total := 0
+	for _, f := range faixas {
+		total += f[1] - f[0] + 1
+	}
+	x := rng.Intn(total)
+	for _, f := range faixas {
+		if tamanho := f[1] - f[0] + 1; x >= tamanho {
+			x -= tamanho
+			continue
+		}
+		return f[0] + x
+	}
+	panic("faixa de CEP não encontrada para " + uf)
+}
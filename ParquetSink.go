@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Tipos lógicos das colunas Parquet: datas viram DATE (dias desde a época,
+// pela data no relógio das lojas) e os valores monetários (vlr_*) DECIMAL
+// com escala de centavos, gravados como INT64. Os demais float64
+// (quantidades, percentuais) ficam DOUBLE.
+const (
+	escalaDecimal   = 2
+	precisaoDecimal = 18
+)
+
+// ParquetSink grava cada coleção normalizada em <tabela>.parquet dentro de
+// Arquivos.Diretorio, para consultar o mesmo conjunto de dados no DuckDB e
+// em outros motores colunares. Campos omitempty viram colunas opcionais,
+// nulas quando zeradas.
+type ParquetSink struct {
+	cfg *Config
+
+	mu       sync.Mutex
+	arquivos map[string]*arquivoParquet
+}
+
+// arquivoParquet é o arquivo de uma tabela. colunas traz, na ordem das
+// folhas do schema, o campo do registro que preenche cada coluna.
+type arquivoParquet struct {
+	mu      sync.Mutex
+	arquivo *arquivoSaida
+	w       *parquet.Writer
+	colunas []campoRegistro
+}
+
+func novoParquetSink(cfg *Config) *ParquetSink {
+	return &ParquetSink{cfg: cfg, arquivos: make(map[string]*arquivoParquet)}
+}
+
+func (s *ParquetSink) Nome() string { return "Parquet" }
+
+func (s *ParquetSink) Abrir(ctx context.Context) error { return nil }
+
+// Aceita descarta as tabelas de consulta do Cassandra: os motores colunares
+// fazem as junções sobre as tabelas normalizadas.
+func (s *ParquetSink) Aceita(r Registro) bool {
+	_, consulta := r.(TabelaConsulta)
+	return !consulta
+}
+
+func (s *ParquetSink) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	if !s.Aceita(registros[0]) {
+		return nil
+	}
+
+	a, err := s.arquivo(registros[0])
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	linhas := make([]parquet.Row, len(registros))
+	for i, r := range registros {
+		linhas[i] = a.linha(r)
+	}
+	_, err = a.w.WriteRows(linhas)
+	return err
+}
+
+// arquivo devolve o arquivo da tabela do registro, criando-o com o schema
+// derivado das tags bson no primeiro uso.
+func (s *ParquetSink) arquivo(modelo Registro) (*arquivoParquet, error) {
+	tabela := modelo.Tabela()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.arquivos[tabela]; ok {
+		return a, nil
+	}
+
+	arquivo, err := criarArquivoEm(s.cfg.Arquivos.Diretorio, tabela+".parquet", false)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, colunas := schemaParquet(modelo)
+	var compressao parquet.WriterOption = parquet.Compression(&parquet.Snappy)
+	if s.cfg.Arquivos.Gzip {
+		compressao = parquet.Compression(&parquet.Gzip)
+	}
+	a := &arquivoParquet{
+		arquivo: arquivo,
+		w: parquet.NewWriter(arquivo, schema, compressao,
+			parquet.MaxRowsPerRowGroup(int64(s.cfg.Arquivos.Parquet.LinhasPorGrupo))),
+		colunas: colunas,
+	}
+
+	s.arquivos[tabela] = a
+	return a, nil
+}
+
+// schemaParquet deriva o schema da tabela do registro e devolve os campos
+// na ordem das colunas do schema, que não é a de declaração da struct.
+func schemaParquet(modelo Registro) (*parquet.Schema, []campoRegistro) {
+	t := reflect.TypeOf(modelo)
+	campos := camposDe(t)
+
+	grupo := parquet.Group{}
+	porColuna := make(map[string]campoRegistro, len(campos))
+	for _, c := range campos {
+		no := tipoParquet(c.coluna, t.Field(c.indice).Type)
+		if c.opcional {
+			no = parquet.Optional(no)
+		}
+		grupo[c.coluna] = no
+		porColuna[c.coluna] = c
+	}
+
+	schema := parquet.NewSchema(modelo.Tabela(), grupo)
+	colunas := make([]campoRegistro, 0, len(campos))
+	for _, caminho := range schema.Columns() {
+		colunas = append(colunas, porColuna[caminho[0]])
+	}
+	return schema, colunas
+}
+
+// tipoParquet converte o tipo Go de um campo no tipo da coluna Parquet.
+func tipoParquet(coluna string, t reflect.Type) parquet.Node {
+	if t == reflect.TypeOf(time.Time{}) {
+		return parquet.Date()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return parquet.Int(64)
+	case reflect.Float64:
+		if strings.HasPrefix(coluna, prefixoValorMonetario) {
+			return parquet.Decimal(escalaDecimal, precisaoDecimal, parquet.Int64Type)
+		}
+		return parquet.Leaf(parquet.DoubleType)
+	case reflect.String:
+		return parquet.String()
+	}
+	panic(fmt.Sprintf("tipo sem equivalente Parquet: %s", t))
+}
+
+// linha converte um registro numa linha Parquet. Colunas opcionais têm
+// nível de definição 1 quando presentes e 0 quando nulas.
+func (a *arquivoParquet) linha(r Registro) parquet.Row {
+	v := reflect.ValueOf(r)
+	linha := make(parquet.Row, len(a.colunas))
+	for i, c := range a.colunas {
+		campo := v.Field(c.indice)
+		definicao := 0
+		if c.opcional {
+			if campo.IsZero() {
+				linha[i] = parquet.NullValue().Level(0, 0, i)
+				continue
+			}
+			definicao = 1
+		}
+		linha[i] = valorParquet(c.coluna, campo.Interface()).Level(0, definicao, i)
+	}
+	return linha
+}
+
+// valorParquet converte um valor no tipo físico da coluna (ver tipoParquet).
+func valorParquet(coluna string, valor any) parquet.Value {
+	switch v := valor.(type) {
+	case time.Time:
+		// A data é a do relógio das lojas, como nas consultas por ano
+		dias := math.Floor(float64(v.Unix()+horasFusoLojas*60*60) / (24 * 60 * 60))
+		return parquet.Int32Value(int32(dias))
+	case int:
+		return parquet.Int64Value(int64(v))
+	case int64:
+		return parquet.Int64Value(v)
+	case float64:
+		if strings.HasPrefix(coluna, prefixoValorMonetario) {
+			return parquet.Int64Value(int64(math.Round(v * math.Pow10(escalaDecimal))))
+		}
+		return parquet.DoubleValue(v)
+	case string:
+		return parquet.ByteArrayValue([]byte(v))
+	}
+	panic(fmt.Sprintf("valor sem equivalente Parquet: %T", valor))
+}
+
+// Flush fecha o row group em andamento; o rodapé só é gravado em Fechar.
+func (s *ParquetSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var erros []error
+	for _, a := range s.arquivos {
+		a.mu.Lock()
+		erros = append(erros, a.w.Flush())
+		a.mu.Unlock()
+	}
+	return errors.Join(erros...)
+}
+
+func (s *ParquetSink) Fechar(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var erros []error
+	for tabela, a := range s.arquivos {
+		erros = append(erros, a.w.Close(), a.arquivo.Close())
+		delete(s.arquivos, tabela)
+	}
+	return errors.Join(erros...)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// cabecalhoNota é o que se decide de uma nota antes de gerá-la: em que
+// PDV e por qual caixa ela foi emitida, quando e com que número. Os campos
+// são compactos porque há um cabeçalho por nota em memória. dat_nota é
+// gravado em UTC, como nas notas abertas pela simulação.
+type cabecalhoNota struct {
+	codPDV   int32
+	codCaixa int32
+	datNota  int64 // segundos desde a época
+	numNota  int64
+}
+
+func (c cabecalhoNota) data() time.Time {
+	return time.Unix(c.datNota, 0).UTC()
+}
+
+// planejarNotas escolhe, para cada nota, a loja pela popularidade e depois
+// um PDV e um caixa em serviço dessa loja, e numera as notas de cada PDV em
+// sequência, na ordem das datas, a partir de num_nota_inicial.
+//
+// A numeração depende de todas as notas do PDV, por isso o planejamento é
+// feito antes da geração, numa passada própria. Quando um PDV recebe mais
+// notas do que a sua faixa autorizada comporta, as excedentes passam para
+// outros PDVs da mesma loja com faixa livre.
+func planejarNotas(cfg *Config, dim *Dimensoes, sorteadores *sorteadoresNotas, calendario *calendarioVendas) ([]cabecalhoNota, error) {
+	if !slices.ContainsFunc(dim.PDVs, func(p PDV) bool { return dim.lojaEmiteNotas(p.CodLoja) }) {
+		return nil, errors.New("nenhuma loja tem PDV e caixa em serviço para emitir notas")
+	}
+
+	cabecalhos := make([]cabecalhoNota, cfg.Volumes.NotasFiscais)
+	paraCadaBloco(cfg, "cabecalho_nota", len(cabecalhos), func(rng *rand.Rand, inicio, fim int) {
+		for i := inicio; i < fim; i++ {
+			codLoja := sorteadores.lojas.sortear(rng) + 1
+			pdvs := dim.PDVsPorLoja[codLoja]
+			caixas := dim.CaixasEmServico[codLoja]
+			cabecalhos[i] = cabecalhoNota{
+				codPDV:   int32(pdvs[rng.Intn(len(pdvs))]),
+				codCaixa: int32(caixas[rng.Intn(len(caixas))]),
+				datNota:  calendario.sortear(rng).Unix(),
+			}
+		}
+	})
+
+	// Notas de cada PDV, em ordem de seq_nota
+	notasPorPDV := make([][]int, len(dim.PDVs)+1)
+	for i, c := range cabecalhos {
+		notasPorPDV[c.codPDV] = append(notasPorPDV[c.codPDV], i)
+	}
+
+	for codLoja, pdvs := range dim.PDVsPorLoja {
+		if err := redistribuirExcedentes(dim, cabecalhos, notasPorPDV, pdvs); err != nil {
+			return nil, fmt.Errorf("loja %d: %w", codLoja, err)
+		}
+	}
+
+	for codPDV, notas := range notasPorPDV {
+		if len(notas) == 0 {
+			continue
+		}
+		slices.SortStableFunc(notas, func(a, b int) int {
+			return cmp.Compare(cabecalhos[a].datNota, cabecalhos[b].datNota)
+		})
+		inicial := int64(dim.PDVs[codPDV-1].NumNotaInicial)
+		for k, i := range notas {
+			cabecalhos[i].numNota = inicial + int64(k)
+		}
+	}
+	return cabecalhos, nil
+}
+
+// redistribuirExcedentes move as notas que não cabem na faixa de um PDV
+// (as de maior seq_nota) para os PDVs da mesma loja que ainda têm faixa
+// livre, em ordem de código.
+func redistribuirExcedentes(dim *Dimensoes, cabecalhos []cabecalhoNota, notasPorPDV [][]int, pdvs []int) error {
+	capacidade := func(codPDV int) int {
+		p := dim.PDVs[codPDV-1]
+		return int(p.NumNotaFinal-p.NumNotaInicial) + 1
+	}
+
+	var excedentes []int
+	for _, codPDV := range pdvs {
+		if notas := notasPorPDV[codPDV]; len(notas) > capacidade(codPDV) {
+			excedentes = append(excedentes, notas[capacidade(codPDV):]...)
+			notasPorPDV[codPDV] = notas[:capacidade(codPDV)]
+		}
+	}
+
+	for _, codPDV := range pdvs {
+		livres := min(capacidade(codPDV)-len(notasPorPDV[codPDV]), len(excedentes))
+		if livres <= 0 {
+			continue
+		}
+		for _, i := range excedentes[:livres] {
+			cabecalhos[i].codPDV = int32(codPDV)
+		}
+		notasPorPDV[codPDV] = append(notasPorPDV[codPDV], excedentes[:livres]...)
+		excedentes = excedentes[livres:]
+	}
+
+	if len(excedentes) > 0 {
+		return fmt.Errorf("%d notas não cabem nas faixas de num_nota dos PDVs (aumente --pdvs ou reduza --notas-fiscais)", len(excedentes))
+	}
+	return nil
+}
+
+// maioresNumNota devolve o maior num_nota planejado de cada PDV com notas.
+func maioresNumNota(cabecalhos []cabecalhoNota) map[int]float64 {
+	maiores := make(map[int]float64)
+	for _, c := range cabecalhos {
+		maiores[int(c.codPDV)] = max(maiores[int(c.codPDV)], float64(c.numNota))
+	}
+	return maiores
+}
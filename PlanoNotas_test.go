@@ -0,0 +1,97 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedistribuirExcedentes(t *testing.T) {
+	// capacidades[i] é o tamanho da faixa de num_nota do PDV i+1
+	casos := []struct {
+		nome        string
+		capacidades []int
+		notas       [][]int // notas iniciais de cada PDV, por índice em cabecalhos
+		esperadas   [][]int // nil quando não cabem
+	}{
+		{
+			nome:        "sem excedentes",
+			capacidades: []int{3, 3},
+			notas:       [][]int{{0, 1}, {2}},
+			esperadas:   [][]int{{0, 1}, {2}},
+		},
+		{
+			nome:        "excedentes vão para o PDV com faixa livre",
+			capacidades: []int{3, 5},
+			notas:       [][]int{{0, 1, 2, 3, 4}, {5}},
+			esperadas:   [][]int{{0, 1, 2}, {5, 3, 4}},
+		},
+		{
+			nome:        "excedentes divididos em ordem de código",
+			capacidades: []int{2, 1, 2, 2},
+			notas:       [][]int{{0, 1, 2, 3, 4}, {}, {5}, {}},
+			esperadas:   [][]int{{0, 1}, {2}, {5, 3}, {4}},
+		},
+		{
+			nome:        "excedentes de mais de um PDV",
+			capacidades: []int{1, 1, 3},
+			notas:       [][]int{{0, 1}, {2, 3}, {}},
+			esperadas:   [][]int{{0}, {2}, {1, 3}},
+		},
+		{
+			nome:        "faixas da loja esgotadas",
+			capacidades: []int{1, 1},
+			notas:       [][]int{{0, 1}, {2}},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			dim := &Dimensoes{}
+			var pdvs []int
+			for i, capacidade := range c.capacidades {
+				dim.PDVs = append(dim.PDVs, PDV{
+					CodPDV:         i + 1,
+					CodLoja:        1,
+					NumNotaInicial: 100,
+					NumNotaFinal:   float64(100 + capacidade - 1),
+				})
+				pdvs = append(pdvs, i+1)
+			}
+
+			total := 0
+			notasPorPDV := make([][]int, len(c.capacidades)+1)
+			for i, notas := range c.notas {
+				notasPorPDV[i+1] = slices.Clone(notas)
+				total += len(notas)
+			}
+			cabecalhos := make([]cabecalhoNota, total)
+			for codPDV, notas := range notasPorPDV {
+				for _, n := range notas {
+					cabecalhos[n].codPDV = int32(codPDV)
+				}
+			}
+
+			err := redistribuirExcedentes(dim, cabecalhos, notasPorPDV, pdvs)
+			if c.esperadas == nil {
+				if err == nil {
+					t.Fatal("esperado erro de notas que não cabem nas faixas")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+
+			for i, esperadas := range c.esperadas {
+				codPDV := i + 1
+				if !slices.Equal(notasPorPDV[codPDV], esperadas) {
+					t.Errorf("notas do PDV %d = %v, esperadas %v", codPDV, notasPorPDV[codPDV], esperadas)
+				}
+				for _, n := range notasPorPDV[codPDV] {
+					if int(cabecalhos[n].codPDV) != codPDV {
+						t.Errorf("nota %d no PDV %d, mas o cabeçalho aponta o PDV %d", n, codPDV, cabecalhos[n].codPDV)
+					}
+				}
+			}
+		})
+	}
+}
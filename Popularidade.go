@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Distribuições de popularidade aceitas em Popularidade
+const (
+	distribuicaoUniforme = "uniforme"
+	distribuicaoZipf     = "zipf"
+	distribuicaoPareto   = "pareto"
+)
+
+var distribuicoesConhecidas = []string{distribuicaoUniforme, distribuicaoZipf, distribuicaoPareto}
+
+// sorteador escolhe posições em [0, n) segundo pesos fixos, por busca
+// binária nos pesos acumulados. Só é lido depois de criado, então pode ser
+// compartilhado entre as goroutines geradoras. Sem pesos, o sorteio é
+// uniforme e consome o fluxo aleatório como rng.Intn, para que a
+// distribuição uniforme reproduza os dados de antes das distribuições.
+type sorteador struct {
+	n         int
+	acumulado []float64
+}
+
+// novoSorteador cria um sorteador com os pesos informados (nil = uniforme).
+func novoSorteador(n int, pesos []float64) *sorteador {
+	s := &sorteador{n: n}
+	if pesos == nil {
+		return s
+	}
+
+	s.acumulado = make([]float64, n)
+	total := 0.0
+	for i, p := range pesos {
+		total += p
+		s.acumulado[i] = total
+	}
+	return s
+}
+
+func (s *sorteador) sortear(rng *rand.Rand) int {
+	if s.acumulado == nil {
+		return rng.Intn(s.n)
+	}
+	x := rng.Float64() * s.acumulado[s.n-1]
+	return sort.Search(s.n, func(i int) bool { return s.acumulado[i] > x })
+}
+
+// pesosPopularidade devolve o peso de cada um dos n itens, ou nil para a
+// distribuição uniforme. Os postos (quem é o mais popular, o segundo...)
+// são uma permutação de rng, para que a popularidade não acompanhe o código.
+func pesosPopularidade(d DistribuicaoConfig, n int, rng *rand.Rand) []float64 {
+	if d.Tipo == distribuicaoUniforme {
+		return nil
+	}
+
+	pesos := make([]float64, n)
+	topo := max(1, int(math.Ceil(float64(n)*d.Topo/100)))
+	for posto, item := range rng.Perm(n) {
+		switch d.Tipo {
+		case distribuicaoZipf:
+			pesos[item] = 1 / math.Pow(float64(posto+1), d.Expoente)
+		case distribuicaoPareto:
+			// Os topo primeiros dividem Participacao% das escolhas
+			if posto < topo {
+				pesos[item] = d.Participacao / float64(topo)
+			} else {
+				pesos[item] = (100 - d.Participacao) / float64(n-topo)
+			}
+		}
+	}
+	return pesos
+}
+
+// String descreve a distribuição no formato aceito por Set.
+func (d *DistribuicaoConfig) String() string {
+	if d == nil {
+		return ""
+	}
+	switch d.Tipo {
+	case distribuicaoZipf:
+		return fmt.Sprintf("%s:%g", d.Tipo, d.Expoente)
+	case distribuicaoPareto:
+		return fmt.Sprintf("%s:%g/%g", d.Tipo, d.Topo, d.Participacao)
+	}
+	return d.Tipo
+}
+
+// Set interpreta "uniforme", "zipf[:expoente]" ou "pareto[:topo/participação]"
+// (por exemplo, zipf:1.2 ou pareto:20/80). Parâmetros omitidos mantêm o
+// valor atual.
+func (d *DistribuicaoConfig) Set(valor string) error {
+	tipo, params, temParams := strings.Cut(strings.TrimSpace(valor), ":")
+	d.Tipo = tipo
+	if !temParams {
+		return nil
+	}
+
+	switch tipo {
+	case distribuicaoZipf:
+		expoente, err := strconv.ParseFloat(params, 64)
+		if err != nil {
+			return fmt.Errorf("expoente inválido %q: %w", params, err)
+		}
+		d.Expoente = expoente
+	case distribuicaoPareto:
+		topo, participacao, ok := strings.Cut(params, "/")
+		if !ok {
+			return fmt.Errorf("parâmetros inválidos %q (esperado topo/participação, como 20/80)", params)
+		}
+		var err error
+		if d.Topo, err = strconv.ParseFloat(topo, 64); err != nil {
+			return fmt.Errorf("topo inválido %q: %w", topo, err)
+		}
+		if d.Participacao, err = strconv.ParseFloat(participacao, 64); err != nil {
+			return fmt.Errorf("participação inválida %q: %w", participacao, err)
+		}
+	default:
+		return fmt.Errorf("a distribuição %q não tem parâmetros", tipo)
+	}
+	return nil
+}
+
+// validar confere os parâmetros da distribuição de uma entidade.
+func (d DistribuicaoConfig) validar(nome string) []error {
+	var erros []error
+	switch d.Tipo {
+	case distribuicaoUniforme:
+	case distribuicaoZipf:
+		if d.Expoente <= 0 {
+			erros = append(erros, fmt.Errorf("%s.expoente deve ser maior que zero (atual: %g)", nome, d.Expoente))
+		}
+	case distribuicaoPareto:
+		if d.Topo <= 0 || d.Topo >= 100 {
+			erros = append(erros, fmt.Errorf("%s.topo deve estar entre 0 e 100 (atual: %g)", nome, d.Topo))
+		}
+		if d.Participacao <= 0 || d.Participacao >= 100 {
+			erros = append(erros, fmt.Errorf("%s.participacao deve estar entre 0 e 100 (atual: %g)", nome, d.Participacao))
+		}
+	default:
+		erros = append(erros, fmt.Errorf("%s.tipo deve ser %s (atual: %q)",
+			nome, strings.Join(distribuicoesConhecidas, ", "), d.Tipo))
+	}
+	return erros
+}
+
+// sorteadoresNotas escolhem o cliente, a loja e os produtos de cada nota.
+type sorteadoresNotas struct {
+	clientes *sorteador
+	produtos *sorteador
+	lojas    *sorteador
+}
+
+// novosSorteadoresNotas monta os sorteadores a partir das dimensões já
+// geradas. Os pesos dos setores multiplicam os dos produtos; lojas sem PDV
+// ou sem caixa em serviço nunca são sorteadas, já que não emitem notas.
+func novosSorteadoresNotas(cfg *Config, dim *Dimensoes) *sorteadoresNotas {
+	p := cfg.Popularidade
+	s := &sorteadoresNotas{}
+
+	pesosClientes := pesosPopularidade(p.Clientes, cfg.Volumes.Clientes, novoRand(cfg, "popularidade_cliente", 0))
+	s.clientes = novoSorteador(cfg.Volumes.Clientes, pesosClientes)
+
+	pesosProdutos := pesosPopularidade(p.Produtos, len(dim.Produtos), novoRand(cfg, "popularidade_produto", 0))
+	if len(p.PesosSetores) > 0 {
+		if pesosProdutos == nil {
+			pesosProdutos = pesosUniformes(len(dim.Produtos))
+		}
+		for i, produto := range dim.Produtos {
+			if peso, ok := p.PesosSetores[setores[produto.CodSetor-1]]; ok {
+				pesosProdutos[i] *= float64(peso)
+			}
+		}
+	}
+	s.produtos = novoSorteador(len(dim.Produtos), pesosProdutos)
+
+	pesosLojas := pesosPopularidade(p.Lojas, cfg.Volumes.Lojas, novoRand(cfg, "popularidade_loja", 0))
+	if pesosLojas == nil {
+		pesosLojas = pesosUniformes(cfg.Volumes.Lojas)
+	}
+	for i := range pesosLojas {
+		if !dim.lojaEmiteNotas(i + 1) {
+			pesosLojas[i] = 0
+		}
+	}
+	s.lojas = novoSorteador(cfg.Volumes.Lojas, pesosLojas)
+	return s
+}
+
+func pesosUniformes(n int) []float64 {
+	pesos := make([]float64, n)
+	for i := range pesos {
+		pesos[i] = 1
+	}
+	return pesos
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSorteador(t *testing.T) {
+	const sorteios = 100_000
+
+	casos := []struct {
+		nome  string
+		pesos []float64
+	}{
+		{"pesos iguais", []float64{1, 1, 1, 1}},
+		{"pesos diferentes", []float64{1, 2, 3, 4}},
+		{"peso zero nunca sai", []float64{3, 0, 1, 0}},
+		{"um só item", []float64{5}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			s := novoSorteador(len(c.pesos), c.pesos)
+			rng := rand.New(rand.NewSource(1))
+
+			contagem := make([]int, len(c.pesos))
+			for i := 0; i < sorteios; i++ {
+				contagem[s.sortear(rng)]++
+			}
+
+			total := 0.0
+			for _, p := range c.pesos {
+				total += p
+			}
+			for i, p := range c.pesos {
+				esperada := p / total
+				obtida := float64(contagem[i]) / sorteios
+				if p == 0 && contagem[i] > 0 {
+					t.Errorf("posição %d, de peso zero, sorteada %d vezes", i, contagem[i])
+				}
+				if math.Abs(obtida-esperada) > 0.01 {
+					t.Errorf("posição %d: frequência %.3f, esperada %.3f", i, obtida, esperada)
+				}
+			}
+		})
+	}
+}
+
+// Sem pesos, o sorteio consome o fluxo como rng.Intn, o que mantém os dados
+// gerados com a distribuição uniforme iguais aos de antes das distribuições.
+func TestSorteadorUniformeComoIntn(t *testing.T) {
+	s := novoSorteador(7, nil)
+	a, b := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+	for i := 0; i < 1000; i++ {
+		if got, want := s.sortear(a), b.Intn(7); got != want {
+			t.Fatalf("sorteio %d = %d, rng.Intn = %d", i, got, want)
+		}
+	}
+}
+
+func TestPesosPopularidade(t *testing.T) {
+	casos := []struct {
+		nome  string
+		d     DistribuicaoConfig
+		n     int
+		pesos []float64 // em ordem decrescente; nil = uniforme
+	}{
+		{"uniforme", DistribuicaoConfig{Tipo: distribuicaoUniforme}, 5, nil},
+		{"zipf 1", DistribuicaoConfig{Tipo: distribuicaoZipf, Expoente: 1}, 4, []float64{1, 1.0 / 2, 1.0 / 3, 1.0 / 4}},
+		{"zipf 2", DistribuicaoConfig{Tipo: distribuicaoZipf, Expoente: 2}, 3, []float64{1, 1.0 / 4, 1.0 / 9}},
+		// Os 2 primeiros (20% de 10) dividem 80% das escolhas
+		{"pareto 20/80", DistribuicaoConfig{Tipo: distribuicaoPareto, Topo: 20, Participacao: 80}, 10,
+			[]float64{40, 40, 2.5, 2.5, 2.5, 2.5, 2.5, 2.5, 2.5, 2.5}},
+		// O topo tem ao menos um item
+		{"pareto com topo arredondado", DistribuicaoConfig{Tipo: distribuicaoPareto, Topo: 1, Participacao: 50}, 3,
+			[]float64{50, 25, 25}},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			pesos := pesosPopularidade(c.d, c.n, rand.New(rand.NewSource(1)))
+			if c.pesos == nil {
+				if pesos != nil {
+					t.Fatalf("pesos = %v, esperado nil", pesos)
+				}
+				return
+			}
+			if len(pesos) != c.n {
+				t.Fatalf("%d pesos, esperados %d", len(pesos), c.n)
+			}
+
+			ordenados := slices.Clone(pesos)
+			slices.Sort(ordenados)
+			slices.Reverse(ordenados)
+			for i := range ordenados {
+				if math.Abs(ordenados[i]-c.pesos[i]) > 1e-12 {
+					t.Fatalf("pesos ordenados = %v, esperados %v", ordenados, c.pesos)
+				}
+			}
+		})
+	}
+}
+
+// Os postos são embaralhados: o item mais popular não é sempre o primeiro.
+func TestPesosPopularidadeEmbaralhados(t *testing.T) {
+	d := DistribuicaoConfig{Tipo: distribuicaoZipf, Expoente: 1}
+	primeiros := make(map[int]bool)
+	for semente := int64(0); semente < 20; semente++ {
+		pesos := pesosPopularidade(d, 10, rand.New(rand.NewSource(semente)))
+		primeiros[slices.Index(pesos, slices.Max(pesos))] = true
+	}
+	if len(primeiros) < 2 {
+		t.Errorf("o item mais popular foi sempre o mesmo: %v", primeiros)
+	}
+}
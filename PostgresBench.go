@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// consultasPostgres são as consultas do README em SQL sobre o modelo
+// normalizado, com as mesmas junções que o MongoDB faz com $lookup.
+var consultasPostgres = map[int]string{
+	1: fmt.Sprintf(`
+		SELECT EXTRACT(YEAR FROM dat_nota + INTERVAL '%d hours') AS ano, SUM(vlr_nota) AS total_vendido
+		FROM nota_fiscal
+		GROUP BY ano
+		ORDER BY ano`, horasFusoLojas),
+	2: `
+		SELECT p.nom_produto, t.quantidade_total
+		FROM (
+			SELECT cod_produto, SUM(qtd_produto) AS quantidade_total
+			FROM item_nota_fiscal
+			GROUP BY cod_produto
+			ORDER BY quantidade_total DESC
+			LIMIT 5
+		) t
+		JOIN produto p ON p.cod_produto = t.cod_produto`,
+	3: `
+		SELECT ci.nom_estado, SUM(n.vlr_nota) AS total_faturado
+		FROM nota_fiscal n
+		JOIN cliente c ON c.cod_cliente = n.cod_cliente
+		JOIN endereco e ON e.cod_endereco = c.cod_endereco
+		JOIN cidade ci ON ci.cod_ibge = e.cod_ibge
+		GROUP BY ci.nom_estado`,
+	4: `
+		SELECT ci.nom_cidade, COUNT(*) AS qtd_fidelizados
+		FROM cliente c
+		JOIN endereco e ON e.cod_endereco = c.cod_endereco
+		JOIN cidade ci ON ci.cod_ibge = e.cod_ibge
+		WHERE c.flg_fidelizado = 'S'
+		GROUP BY ci.nom_cidade`,
+	5: `
+		SELECT s.nom_setor, AVG(i.vlr_venda - i.vlr_custo) AS lucro_medio
+		FROM item_nota_fiscal i
+		JOIN produto p ON p.cod_produto = i.cod_produto
+		JOIN setor s ON s.cod_setor = p.cod_setor
+		GROUP BY s.nom_setor`,
+	consultaClientePorCPF: `
+		SELECT cod_cliente, nom_cliente
+		FROM cliente
+		WHERE num_cpf = $1`,
+}
+
+// executorPostgres roda as consultas em SQL.
+type executorPostgres struct {
+	cfg  *Config
+	pool *pgxpool.Pool
+	cpf  string // procurado na consulta 6
+}
+
+func (e *executorPostgres) Nome() string { return "PostgreSQL" }
+
+func (e *executorPostgres) Abrir(ctx context.Context) error {
+	pool, err := conectarPostgres(ctx, e.cfg)
+	if err != nil {
+		return err
+	}
+	e.pool = pool
+	return nil
+}
+
+func (e *executorPostgres) Versao(ctx context.Context) (string, error) {
+	var versao string
+	err := e.pool.QueryRow(ctx, `SHOW server_version`).Scan(&versao)
+	return versao, err
+}
+
+func (e *executorPostgres) Metadados(ctx context.Context) (Metadados, error) {
+	m := Metadados{Chave: chaveMetadados}
+	err := e.pool.QueryRow(ctx, `SELECT semente, escala, data_referencia, volumes FROM metadados WHERE chave = $1`, m.Chave).
+		Scan(&m.Semente, &m.Escala, &m.DataReferencia, &m.Volumes)
+	return m, err
+}
+
+func (e *executorPostgres) Preparar(ctx context.Context, c Consulta) error {
+	if c.Numero != consultaClientePorCPF {
+		return nil
+	}
+	return e.pool.QueryRow(ctx, `SELECT num_cpf FROM cliente WHERE cod_cliente = $1`, codClienteConsulta).Scan(&e.cpf)
+}
+
+func (e *executorPostgres) Executar(ctx context.Context, c Consulta) (int, error) {
+	sql, ok := consultasPostgres[c.Numero]
+	if !ok {
+		return 0, fmt.Errorf("consulta %d sem SQL no PostgreSQL", c.Numero)
+	}
+
+	var args []any
+	if c.Numero == consultaClientePorCPF {
+		args = append(args, e.cpf)
+	}
+
+	linhas, err := e.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer linhas.Close()
+
+	n := 0
+	for linhas.Next() {
+		n++
+	}
+	return n, linhas.Err()
+}
+
+func (e *executorPostgres) Fechar(ctx context.Context) error {
+	if e.pool != nil {
+		e.pool.Close()
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// chaveEstrangeira liga uma coluna à chave primária de outra tabela.
+type chaveEstrangeira struct {
+	coluna     string
+	referencia string // tabela referenciada, cuja chave tem o nome da coluna
+}
+
+// chavesEstrangeirasPostgres são as relações do modelo normalizado. A
+// coluna referenciada tem sempre o mesmo nome da coluna que a referencia.
+var chavesEstrangeirasPostgres = map[string][]chaveEstrangeira{
+	"endereco":         {{"cod_ibge", "cidade"}},
+	"produto":          {{"cod_fornecedor", "fornecedor"}, {"cod_setor", "setor"}, {"cod_unidade", "unidade"}, {"cod_promocao", "promocao"}},
+	"loja":             {{"cod_endereco", "endereco"}},
+	"pdv":              {{"cod_loja", "loja"}},
+	"caixa":            {{"cod_loja", "loja"}},
+	"cliente":          {{"cod_endereco", "endereco"}},
+	"nota_fiscal":      {{"cod_pdv", "pdv"}, {"cod_caixa", "caixa"}, {"cod_cliente", "cliente"}},
+	"item_nota_fiscal": {{"seq_nota", "nota_fiscal"}, {"cod_produto", "produto"}},
+}
+
+// indicesConsultaPostgres indexam as colunas filtradas e agrupadas pelas
+// consultas do README. O PostgreSQL não indexa chaves estrangeiras sozinho.
+var indicesConsultaPostgres = map[string][]string{
+	"item_nota_fiscal": {"cod_produto"},
+	"nota_fiscal":      {"dat_nota", "cod_cliente"},
+	"cliente":          {"flg_fidelizado", "num_cpf"},
+}
+
+// colunaSQL é uma coluna esperada de uma tabela do PostgreSQL. tipo segue
+// o texto devolvido por format_type, para comparar direto com o catálogo.
+type colunaSQL struct {
+	nome     string
+	tipo     string
+	opcional bool
+}
+
+// tabelaSQL é a definição esperada de uma tabela, derivada de uma struct.
+type tabelaSQL struct {
+	nome         string
+	colunas      []colunaSQL
+	chave        []string
+	estrangeiras []chaveEstrangeira
+}
+
+// tabelaSQLDe deriva a tabela das tags bson e chave do registro modelo.
+// Campos omitempty aceitam NULL; os demais são NOT NULL.
+func tabelaSQLDe(r Registro) tabelaSQL {
+	t := reflect.TypeOf(r)
+	tabela := tabelaSQL{nome: r.Tabela(), estrangeiras: chavesEstrangeirasPostgres[r.Tabela()]}
+
+	var clustering []string
+	for _, c := range camposDe(t) {
+		tabela.colunas = append(tabela.colunas, colunaSQL{
+			nome:     c.coluna,
+			tipo:     tipoSQL(c.coluna, t.Field(c.indice).Type),
+			opcional: c.opcional,
+		})
+		switch c.chave {
+		case chaveParticao:
+			tabela.chave = append(tabela.chave, c.coluna)
+		case chaveClustering:
+			clustering = append(clustering, c.coluna)
+		}
+	}
+	tabela.chave = append(tabela.chave, clustering...)
+	return tabela
+}
+
+// tipoSQL converte o tipo Go de um campo no tipo da coluna. Valores
+// monetários (vlr_*) são numeric com a precisão e a escala do Parquet.
+func tipoSQL(coluna string, t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "timestamp without time zone"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int32:
+		return "integer"
+	case reflect.Int64:
+		return "bigint"
+	case reflect.Float64:
+		if strings.HasPrefix(coluna, prefixoValorMonetario) {
+			return fmt.Sprintf("numeric(%d,%d)", precisaoDecimal, escalaDecimal)
+		}
+		return "double precision"
+	case reflect.String:
+		return "text"
+	case reflect.Bool:
+		return "boolean"
+	}
+	panic(fmt.Sprintf("tipo sem equivalente SQL: %s", t))
+}
+
+// nomeChaveEstrangeira dá nome às restrições, para que check as encontre.
+func nomeChaveEstrangeira(tabela string, fk chaveEstrangeira) string {
+	return "fk_" + tabela + "_" + fk.coluna
+}
+
+// ddl monta o CREATE TABLE da tabela, com chave primária e estrangeiras.
+func (t tabelaSQL) ddl() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (\n", t.nome)
+	for _, c := range t.colunas {
+		nulo := " NOT NULL"
+		if c.opcional {
+			nulo = ""
+		}
+		fmt.Fprintf(&b, "  %s %s%s,\n", c.nome, c.tipo, nulo)
+	}
+	for _, fk := range t.estrangeiras {
+		fmt.Fprintf(&b, "  CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s),\n",
+			nomeChaveEstrangeira(t.nome, fk), fk.coluna, fk.referencia, fk.coluna)
+	}
+	fmt.Fprintf(&b, "  PRIMARY KEY (%s)\n)", strings.Join(t.chave, ", "))
+	return b.String()
+}
+
+// esquemaPostgres cria as tabelas normalizadas, com chaves estrangeiras,
+// e os índices das consultas. As tabelas de consulta do Cassandra não
+// existem no PostgreSQL: as consultas usam junções.
+type esquemaPostgres struct {
+	cfg *Config
+}
+
+func (e *esquemaPostgres) Nome() string { return "PostgreSQL" }
+
+// Criar cria as tabelas na ordem de geração, que já põe as referenciadas
+// antes das que as referenciam.
+func (e *esquemaPostgres) Criar(ctx context.Context) error {
+	pool, err := conectarPostgres(ctx, e.cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	var comandos []string
+	for _, modelo := range tabelas {
+		comandos = append(comandos, tabelaSQLDe(modelo).ddl())
+		for _, coluna := range indicesConsultaPostgres[modelo.Tabela()] {
+			comandos = append(comandos, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_%s_idx ON %s (%s)",
+				modelo.Tabela(), coluna, modelo.Tabela(), coluna))
+		}
+	}
+
+	for _, sql := range comandos {
+		if _, err := pool.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("%w\n%s", err, sql)
+		}
+	}
+	return nil
+}
+
+// Remover apaga as tabelas na ordem inversa da criação.
+func (e *esquemaPostgres) Remover(ctx context.Context) error {
+	pool, err := conectarPostgres(ctx, e.cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	for i := len(tabelas) - 1; i >= 0; i-- {
+		modelo := tabelas[i]
+		if _, err := pool.Exec(ctx, "DROP TABLE IF EXISTS "+modelo.Tabela()); err != nil {
+			return fmt.Errorf("tabela %s: %w", modelo.Tabela(), err)
+		}
+	}
+	return nil
+}
+
+func (e *esquemaPostgres) Verificar(ctx context.Context) ([]string, error) {
+	pool, err := conectarPostgres(ctx, e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+
+	// Colunas existentes no schema corrente, por tabela
+	existentes := make(map[string]map[string]colunaSQL)
+	linhas, err := pool.Query(ctx, `
+		SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind = 'r'
+		  AND a.attnum > 0 AND NOT a.attisdropped
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for linhas.Next() {
+		var tabela string
+		var coluna colunaSQL
+		if err := linhas.Scan(&tabela, &coluna.nome, &coluna.tipo, &coluna.opcional); err != nil {
+			linhas.Close()
+			return nil, err
+		}
+		if existentes[tabela] == nil {
+			existentes[tabela] = make(map[string]colunaSQL)
+		}
+		existentes[tabela][coluna.nome] = coluna
+	}
+	if err := linhas.Err(); err != nil {
+		return nil, err
+	}
+
+	restricoes := make(map[string]bool)
+	linhas, err = pool.Query(ctx, `
+		SELECT conname FROM pg_constraint
+		WHERE contype = 'f' AND connamespace = current_schema()::regnamespace
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for linhas.Next() {
+		var nome string
+		if err := linhas.Scan(&nome); err != nil {
+			linhas.Close()
+			return nil, err
+		}
+		restricoes[nome] = true
+	}
+	if err := linhas.Err(); err != nil {
+		return nil, err
+	}
+
+	var divergencias []string
+	for _, modelo := range tabelas {
+		esperada := tabelaSQLDe(modelo)
+		atuais, ok := existentes[esperada.nome]
+		if !ok {
+			divergencias = append(divergencias, fmt.Sprintf("tabela %s não existe", esperada.nome))
+			continue
+		}
+
+		for _, c := range esperada.colunas {
+			atual, ok := atuais[c.nome]
+			switch {
+			case !ok:
+				divergencias = append(divergencias, fmt.Sprintf("%s.%s não existe (esperado %s)", esperada.nome, c.nome, c.tipo))
+			case atual.tipo != c.tipo:
+				divergencias = append(divergencias, fmt.Sprintf("%s.%s é %s (esperado %s)", esperada.nome, c.nome, atual.tipo, c.tipo))
+			case atual.opcional != c.opcional:
+				divergencias = append(divergencias, fmt.Sprintf("%s.%s difere na restrição NOT NULL", esperada.nome, c.nome))
+			}
+		}
+		for _, fk := range esperada.estrangeiras {
+			if nome := nomeChaveEstrangeira(esperada.nome, fk); !restricoes[nome] {
+				divergencias = append(divergencias, fmt.Sprintf("tabela %s sem a chave estrangeira %s", esperada.nome, nome))
+			}
+		}
+	}
+	return divergencias, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresSink grava cada tabela normalizada na tabela de mesmo nome com
+// COPY FROM STDIN (protocolo binário), um COPY por lote recebido. Como as
+// chaves estrangeiras são verificadas a cada COPY, a ordem de geração (e a
+// de Lote.Descarregar, nota antes dos itens) garante que as referências já
+// existam.
+type PostgresSink struct {
+	cfg  *Config
+	pool *pgxpool.Pool
+}
+
+func novoPostgresSink(cfg *Config) *PostgresSink {
+	return &PostgresSink{cfg: cfg}
+}
+
+func (s *PostgresSink) Nome() string { return "PostgreSQL" }
+
+func (s *PostgresSink) Abrir(ctx context.Context) error {
+	pool, err := conectarPostgres(ctx, s.cfg)
+	if err != nil {
+		return err
+	}
+	s.pool = pool
+	return nil
+}
+
+// Aceita descarta as tabelas de consulta do Cassandra: as consultas do
+// PostgreSQL usam junções sobre as tabelas normalizadas.
+func (s *PostgresSink) Aceita(r Registro) bool {
+	_, consulta := r.(TabelaConsulta)
+	return !consulta
+}
+
+// Escrever copia o lote inteiro num único COPY. O PostgreSQL aborta o COPY
+// no primeiro erro, então uma falha é atribuída a todos os registros.
+func (s *PostgresSink) Escrever(ctx context.Context, tabela string, registros []Registro) error {
+	if !s.Aceita(registros[0]) {
+		return nil
+	}
+
+	linhas := pgx.CopyFromSlice(len(registros), func(i int) ([]any, error) {
+		return valoresPostgres(registros[i]), nil
+	})
+	_, err := s.pool.CopyFrom(ctx, pgx.Identifier{tabela}, colunasDe(registros[0]), linhas)
+	if err == nil {
+		return nil
+	}
+
+	falhas := make([]FalhaRegistro, len(registros))
+	for i, r := range registros {
+		falhas[i] = FalhaRegistro{Chave: chaveDe(r), Err: err}
+	}
+	return &ErroLote{Tabela: tabela, Total: len(registros), Falhas: falhas}
+}
+
+// valoresPostgres devolve os valores na ordem de colunasDe, com NULL nos
+// campos omitempty zerados (cod_promocao de produto sem promoção), para
+// não violar a chave estrangeira.
+func valoresPostgres(r Registro) []any {
+	v := reflect.ValueOf(r)
+	campos := camposDe(v.Type())
+	valores := make([]any, len(campos))
+	for i, c := range campos {
+		campo := v.Field(c.indice)
+		if c.opcional && campo.IsZero() {
+			continue
+		}
+		valores[i] = campo.Interface()
+	}
+	return valores
+}
+
+func (s *PostgresSink) Flush(ctx context.Context) error { return nil }
+
+func (s *PostgresSink) Fechar(ctx context.Context) error {
+	if s.pool != nil {
+		s.pool.Close()
+	}
+	return nil
+}
+
+// conectarPostgres abre um pool de conexões com Postgres.URI. O pool cresce
+// até o maior entre NumGoroutines e o padrão do pgx, para que as goroutines
+// geradoras não esperem por conexão.
+func conectarPostgres(ctx context.Context, cfg *Config) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.Postgres.URI)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.MaxConns = max(poolCfg.MaxConns, int32(cfg.NumGoroutines))
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	fmt.Println("Conectado ao PostgreSQL com sucesso!")
+	return pool, nil
+}
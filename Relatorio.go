@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"html/template"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marcadores da região de resultados no README.md e no index.html. Tudo o
+// que está entre eles é substituído pelo comando report.
+const (
+	marcadorInicio = "<!-- resultados:inicio -->"
+	marcadorFim    = "<!-- resultados:fim -->"
+)
+
+// coresBancos são as cores das barras de cada banco nos gráficos.
+var coresBancos = map[string]string{
+	"MongoDB":    "#4db33d",
+	"Cassandra":  "#1287b1",
+	"PostgreSQL": "#336791",
+}
+
+// corPadrao pinta bancos sem cor definida em coresBancos.
+const corPadrao = "#7f8c8d"
+
+// executarRelatorio trata "report resultados.json [flags]": reescreve a
+// tabela de resultados do README.md e o slide de resultados do index.html
+// com as medianas de um arquivo gravado pelo bench.
+func executarRelatorio(args []string) {
+	if len(args) < 1 {
+		log.Fatalf("Uso: report resultados.json [flags]")
+	}
+	cfg, ok := configurar("report", args[1:])
+	if !ok {
+		return
+	}
+
+	execucao, err := lerExecucaoBench(args[0])
+	if err != nil {
+		log.Fatalf("Erro ao ler resultados: %v", err)
+	}
+	rel := montarRelatorio(execucao)
+
+	if err := reescreverRegiao(cfg.Relatorio.README, rel.markdown()); err != nil {
+		log.Fatalf("Erro ao atualizar %s: %v", cfg.Relatorio.README, err)
+	}
+	fmt.Printf("Tabela de resultados atualizada em %s\n", cfg.Relatorio.README)
+
+	slide, err := rel.html()
+	if err != nil {
+		log.Fatalf("Erro ao montar o slide: %v", err)
+	}
+	if err := reescreverRegiao(cfg.Relatorio.HTML, slide); err != nil {
+		log.Fatalf("Erro ao atualizar %s: %v", cfg.Relatorio.HTML, err)
+	}
+	fmt.Printf("Slide de resultados atualizado em %s\n", cfg.Relatorio.HTML)
+}
+
+// relatorio é a tabela de resultados: uma linha por consulta e uma coluna
+// (mediana em ms) por banco.
+type relatorio struct {
+	Bancos []string
+	Linhas []linhaRelatorio
+}
+
+type linhaRelatorio struct {
+	Descricao string
+	Tempos    []float64 // ms, na ordem de Bancos; NaN se o banco não rodou a consulta
+	Diferenca string
+}
+
+// montarRelatorio organiza os resultados na ordem das consultas e na ordem
+// em que os bancos aparecem no arquivo.
+func montarRelatorio(e *ExecucaoBench) relatorio {
+	var rel relatorio
+	for _, r := range e.Resultados {
+		if !slices.Contains(rel.Bancos, r.Banco) {
+			rel.Bancos = append(rel.Bancos, r.Banco)
+		}
+	}
+
+	for _, c := range consultas {
+		linha := linhaRelatorio{Descricao: c.Descricao, Tempos: make([]float64, len(rel.Bancos))}
+		encontrada := false
+		for i, banco := range rel.Bancos {
+			linha.Tempos[i] = math.NaN()
+			for _, r := range e.Resultados {
+				if r.Consulta.Numero == c.Numero && r.Banco == banco {
+					linha.Tempos[i] = float64(r.Mediana) / float64(time.Millisecond)
+					encontrada = true
+				}
+			}
+		}
+		if encontrada {
+			linha.Diferenca = diferenca(rel.Bancos, linha.Tempos)
+			rel.Linhas = append(rel.Linhas, linha)
+		}
+	}
+	return rel
+}
+
+// diferenca descreve o banco mais rápido em relação ao segundo mais rápido,
+// como na tabela original ("MongoDB 73% mais rápido").
+func diferenca(bancos []string, tempos []float64) string {
+	var ordem []int
+	for i, t := range tempos {
+		if !math.IsNaN(t) {
+			ordem = append(ordem, i)
+		}
+	}
+	if len(ordem) < 2 {
+		return "-"
+	}
+	slices.SortFunc(ordem, func(a, b int) int { return cmp.Compare(tempos[a], tempos[b]) })
+
+	primeiro, segundo := tempos[ordem[0]], tempos[ordem[1]]
+	pct := math.Round((1 - primeiro/segundo) * 100)
+	if pct == 0 {
+		return "Empate"
+	}
+	return fmt.Sprintf("%s %.0f%% mais rápido", bancos[ordem[0]], pct)
+}
+
+// formatoNumero define os separadores usados num documento.
+type formatoNumero struct {
+	milhar, decimal string
+}
+
+var (
+	formatoREADME = formatoNumero{milhar: ",", decimal: "."} // como em "3,500"
+	formatoHTML   = formatoNumero{milhar: ".", decimal: ","} // como em "3.500"
+)
+
+// ms formata um tempo em milissegundos: inteiro a partir de 100 ms, com uma
+// casa decimal abaixo disso, para não zerar consultas rápidas.
+func (f formatoNumero) ms(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	if v < 100 {
+		return strings.Replace(strconv.FormatFloat(v, 'f', 1, 64), ".", f.decimal, 1)
+	}
+
+	digitos := strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+	var b strings.Builder
+	for i, d := range digitos {
+		if i > 0 && (len(digitos)-i)%3 == 0 {
+			b.WriteString(f.milhar)
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// markdown devolve a tabela no formato do README.
+func (r relatorio) markdown() string {
+	var b strings.Builder
+	b.WriteString("| Consulta | " + strings.Join(r.Bancos, " | ") + " | Diferença % |\n")
+	b.WriteString("|----------|")
+	for _, banco := range r.Bancos {
+		b.WriteString(strings.Repeat("-", len(banco)+2) + "|")
+	}
+	b.WriteString("-------------|\n")
+
+	for _, l := range r.Linhas {
+		b.WriteString("| " + l.Descricao + " |")
+		for _, t := range l.Tempos {
+			b.WriteString(" " + formatoREADME.ms(t) + " |")
+		}
+		b.WriteString(" " + l.Diferenca + " |\n")
+	}
+	return b.String()
+}
+
+// Dimensões do gráfico de barras do slide, em unidades do viewBox
+const (
+	larguraGrafico = 860
+	alturaGrafico  = 300
+	margemGrafico  = 40  // espaço para a legenda (acima) e os rótulos (abaixo)
+	vaoGrupos      = 0.3 // fração de cada grupo deixada em branco
+)
+
+// graficoSVG é o que o template precisa para desenhar as barras.
+type graficoSVG struct {
+	Largura, Altura int
+	Grupos          []grupoSVG
+	Legenda         []barraSVG
+}
+
+type grupoSVG struct {
+	Rotulo  string
+	RotuloX float64
+	RotuloY float64
+	Barras  []barraSVG
+}
+
+type barraSVG struct {
+	Nome, Cor, Valor      string
+	X, Y, Largura, Altura float64
+	Centro                float64 // x do rótulo com o valor
+}
+
+// grafico calcula as barras agrupadas por consulta, na escala do maior tempo.
+func (r relatorio) grafico() graficoSVG {
+	g := graficoSVG{Largura: larguraGrafico, Altura: alturaGrafico}
+
+	maior := 0.0
+	for _, l := range r.Linhas {
+		for _, t := range l.Tempos {
+			if !math.IsNaN(t) {
+				maior = math.Max(maior, t)
+			}
+		}
+	}
+	if maior == 0 || len(r.Linhas) == 0 {
+		return g
+	}
+
+	areaAltura := float64(alturaGrafico - 2*margemGrafico)
+	larguraGrupo := float64(larguraGrafico) / float64(len(r.Linhas))
+	larguraBarra := larguraGrupo * (1 - vaoGrupos) / float64(len(r.Bancos))
+
+	for i, l := range r.Linhas {
+		x0 := float64(i)*larguraGrupo + larguraGrupo*vaoGrupos/2
+		grupo := grupoSVG{
+			Rotulo:  l.Descricao,
+			RotuloX: float64(i)*larguraGrupo + larguraGrupo/2,
+			RotuloY: float64(alturaGrafico - margemGrafico/2),
+		}
+		for j, t := range l.Tempos {
+			if math.IsNaN(t) {
+				continue
+			}
+			altura := t / maior * areaAltura
+			grupo.Barras = append(grupo.Barras, barraSVG{
+				Nome:    r.Bancos[j],
+				Cor:     corDe(r.Bancos[j]),
+				Valor:   formatoHTML.ms(t),
+				X:       x0 + float64(j)*larguraBarra,
+				Y:       float64(alturaGrafico-margemGrafico) - altura,
+				Largura: larguraBarra,
+				Altura:  altura,
+				Centro:  x0 + (float64(j)+0.5)*larguraBarra,
+			})
+		}
+		g.Grupos = append(g.Grupos, grupo)
+	}
+
+	for j, banco := range r.Bancos {
+		g.Legenda = append(g.Legenda, barraSVG{Nome: banco, Cor: corDe(banco), X: float64(j * 140)})
+	}
+	return g
+}
+
+func corDe(banco string) string {
+	if cor, ok := coresBancos[banco]; ok {
+		return cor
+	}
+	return corPadrao
+}
+
+// templateSlide é o conteúdo do slide de resultados, com a indentação do
+// index.html.
+var templateSlide = template.Must(template.New("slide").Parse(`
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Consulta</th>
+                            {{- range .Bancos}}
+                            <th>{{.}}</th>
+                            {{- end}}
+                            <th>Diferença %</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{- range .Linhas}}
+                        <tr>
+                            <td>{{.Descricao}}</td>
+                            {{- range .Tempos}}
+                            <td>{{call $.Ms .}}</td>
+                            {{- end}}
+                            <td>{{.Diferenca}}</td>
+                        </tr>
+                        {{- end}}
+                    </tbody>
+                </table>
+                {{- with .Grafico}}
+                <svg viewBox="0 0 {{.Largura}} {{.Altura}}" width="100%" role="img" aria-label="Mediana das consultas em ms" font-size="11">
+                    {{- range .Legenda}}
+                    <rect x="{{.X}}" y="0" width="12" height="12" fill="{{.Cor}}"/>
+                    <text x="{{.X}}" y="10" dx="16">{{.Nome}}</text>
+                    {{- end}}
+                    {{- range .Grupos}}
+                    {{- range .Barras}}
+                    <rect x="{{printf "%.1f" .X}}" y="{{printf "%.1f" .Y}}" width="{{printf "%.1f" .Largura}}" height="{{printf "%.1f" .Altura}}" fill="{{.Cor}}"><title>{{.Nome}}: {{.Valor}} ms</title></rect>
+                    <text x="{{printf "%.1f" .Centro}}" y="{{printf "%.1f" .Y}}" dy="-4" text-anchor="middle">{{.Valor}}</text>
+                    {{- end}}
+                    <text x="{{printf "%.1f" .RotuloX}}" y="{{printf "%.1f" .RotuloY}}" text-anchor="middle">{{.Rotulo}}</text>
+                    {{- end}}
+                </svg>
+                {{- end}}
+`))
+
+// html devolve a tabela e o gráfico do slide de resultados.
+func (r relatorio) html() (string, error) {
+	dados := struct {
+		Bancos  []string
+		Linhas  []linhaRelatorio
+		Grafico graficoSVG
+		Ms      func(float64) string
+	}{r.Bancos, r.Linhas, r.grafico(), formatoHTML.ms}
+
+	var b bytes.Buffer
+	if err := templateSlide.Execute(&b, dados); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(b.String(), "\n"), nil
+}
+
+// reescreverRegiao troca o conteúdo entre os marcadores de um arquivo,
+// preservando a indentação da linha do marcador de fim.
+func reescreverRegiao(caminho, conteudo string) error {
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return err
+	}
+	texto := string(dados)
+
+	inicio := strings.Index(texto, marcadorInicio)
+	fim := strings.Index(texto, marcadorFim)
+	if inicio < 0 || fim < inicio {
+		return fmt.Errorf("marcadores %s e %s não encontrados", marcadorInicio, marcadorFim)
+	}
+	inicio += len(marcadorInicio)
+	// Recua até o começo da linha do marcador de fim, mantendo a indentação
+	fim = strings.LastIndex(texto[:fim], "\n") + 1
+
+	novo := texto[:inicio] + "\n" + conteudo + texto[fim:]
+	return os.WriteFile(caminho, []byte(novo), 0o644)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+)
+
+// tabelas tem um modelo de cada entidade gravada, na ordem de geração. Os
+// schemas dos bancos são derivados destas structs.
+var tabelas = []Registro{
+	Cidade{},
+	Endereco{},
+	Fornecedor{},
+	Setor{},
+	Unidade{},
+	Promocao{},
+	Produto{},
+	Loja{},
+	PDV{},
+	Caixa{},
+	Cliente{},
+	NotaFiscal{},
+	ItemNotaFiscal{},
+	Metadados{},
+}
+
+// Esquema cria, remove e confere as estruturas que um banco precisa para
+// receber os dados gerados.
+type Esquema interface {
+	Nome() string
+	Criar(ctx context.Context) error
+	Remover(ctx context.Context) error
+	// Verificar devolve as divergências entre o schema do banco e o esperado.
+	Verificar(ctx context.Context) ([]string, error)
+}
+
+var acoesSchema = []string{"create", "drop", "check"}
+
+// executarSchema trata "schema create|drop|check" para os bancos em --sinks.
+func executarSchema(args []string) {
+	if len(args) == 0 || !slices.Contains(acoesSchema, args[0]) {
+		log.Fatalf("Uso: schema create|drop|check [flags]")
+	}
+	acao := args[0]
+
+	cfg, ok := configurar("schema "+acao, args[1:])
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+
+	divergente := false
+	for _, esquema := range criarEsquemas(&cfg) {
+		switch acao {
+		case "create":
+			if err := esquema.Criar(ctx); err != nil {
+				log.Fatalf("Erro ao criar schema no %s: %v", esquema.Nome(), err)
+			}
+			fmt.Printf("Schema criado no %s\n", esquema.Nome())
+
+		case "drop":
+			if err := esquema.Remover(ctx); err != nil {
+				log.Fatalf("Erro ao remover schema no %s: %v", esquema.Nome(), err)
+			}
+			fmt.Printf("Schema removido no %s\n", esquema.Nome())
+
+		case "check":
+			divergencias, err := esquema.Verificar(ctx)
+			if err != nil {
+				log.Fatalf("Erro ao verificar schema no %s: %v", esquema.Nome(), err)
+			}
+			if len(divergencias) == 0 {
+				fmt.Printf("Schema do %s confere com o esperado\n", esquema.Nome())
+				continue
+			}
+
+			divergente = true
+			fmt.Printf("Schema do %s diverge do esperado:\n", esquema.Nome())
+			for _, d := range divergencias {
+				fmt.Printf("  - %s\n", d)
+			}
+		}
+	}
+
+	if divergente {
+		os.Exit(1)
+	}
+}
+
+// criarEsquemas devolve os esquemas dos bancos listados em --sinks.
+func criarEsquemas(cfg *Config) []Esquema {
+	var esquemas []Esquema
+	for _, nome := range cfg.Sinks {
+		switch nome {
+		case sinkMongoDB:
+			esquemas = append(esquemas, &esquemaMongo{cfg: cfg})
+		case sinkCassandra:
+			esquemas = append(esquemas, &esquemaCassandra{cfg: cfg})
+		case sinkPostgres:
+			esquemas = append(esquemas, &esquemaPostgres{cfg: cfg})
+		}
+	}
+	return esquemas
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// tamanhoBloco é a quantidade de registros gerados com um mesmo fluxo
+// aleatório. Como a divisão em blocos não depende do número de goroutines,
+// a mesma semente sempre produz exatamente os mesmos registros.
+const tamanhoBloco = 1000
+
+// derivarSemente combina a semente da execução com o nome da entidade e o
+// índice do bloco, devolvendo uma semente independente para cada fluxo.
+func derivarSemente(semente int64, entidade string, bloco int) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(entidade))
+	x := uint64(semente) ^ h.Sum64() ^ uint64(bloco)*0x9e3779b97f4a7c15
+
+	// Finalizador do splitmix64, para espalhar sementes vizinhas
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return int64(x)
+}
+
+// novoRand devolve o fluxo aleatório de um bloco de uma entidade.
+func novoRand(cfg *Config, entidade string, bloco int) *rand.Rand {
+	return rand.New(rand.NewSource(derivarSemente(cfg.Semente, entidade, bloco)))
+}
+
+// paraCadaBloco distribui os registros [0, total) de uma entidade entre as
+// goroutines configuradas. Cada bloco recebe seu próprio *rand.Rand e é
+// processado por uma única goroutine, então fn não compartilha estado
+// aleatório com as demais.
+func paraCadaBloco(cfg *Config, entidade string, total int, fn func(rng *rand.Rand, inicio, fim int)) {
+	numBlocos := (total + tamanhoBloco - 1) / tamanhoBloco
+	var proximo atomic.Int64
+
+	var wg sync.WaitGroup
+	for g := 0; g < min(cfg.NumGoroutines, numBlocos); g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				bloco := int(proximo.Add(1) - 1)
+				if bloco >= numBlocos {
+					return
+				}
+
+				inicio := bloco * tamanhoBloco
+				fim := min(inicio+tamanhoBloco, total)
+				fn(novoRand(cfg, entidade, bloco), inicio, fim)
+			}
+		}()
+	}
+	wg.Wait()
+}
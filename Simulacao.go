@@ -0,0 +1,430 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OLTP são as operações de um caixa em atendimento, implementadas por banco.
+type OLTP interface {
+	Nome() string
+	Abrir(ctx context.Context) error
+	// Metadados lê os metadados da geração, com a numeração das notas.
+	Metadados(ctx context.Context) (Metadados, error)
+	// GravarNumeracao atualiza a numeração das notas nos metadados.
+	GravarNumeracao(ctx context.Context, maiorSeqNota int, maioresNumNota string) error
+	AbrirNota(ctx context.Context, nota NotaFiscal) error
+	LerProduto(ctx context.Context, codProduto int) (Produto, error)
+	AdicionarItem(ctx context.Context, item ItemNotaFiscal) error
+	BuscarCliente(ctx context.Context, codCliente int) (Cliente, error)
+	FecharNota(ctx context.Context, seqNota int, vlrNota float64) error
+	Fechar(ctx context.Context) error
+}
+
+// Operações medidas pela simulação, na ordem em que são exibidas
+const (
+	opAbrirNota     = "abrir nota"
+	opLerProduto    = "ler produto"
+	opAdicionarItem = "adicionar item"
+	opBuscarCliente = "buscar cliente"
+	opFecharNota    = "fechar nota"
+)
+
+var operacoesOLTP = []string{opAbrirNota, opLerProduto, opAdicionarItem, opBuscarCliente, opFecharNota}
+
+// maxOpsPorSegundo é um tique do ritmo por microssegundo. Bem antes disso
+// o ticker deixa de acompanhar, e acima de um por nanossegundo o intervalo
+// seria zero.
+const maxOpsPorSegundo = 1_000_000
+
+// criarOLTPs devolve as implementações dos bancos listados em --sinks.
+func criarOLTPs(cfg *Config) []OLTP {
+	var bancos []OLTP
+	for _, nome := range cfg.Sinks {
+		switch nome {
+		case sinkMongoDB:
+			bancos = append(bancos, &oltpMongo{cfg: cfg})
+		case sinkCassandra:
+			bancos = append(bancos, &oltpCassandra{cfg: cfg})
+		}
+	}
+	return bancos
+}
+
+// executarSimulacao trata "simulate": PDVs virtuais atendem clientes contra
+// cada banco de --sinks, um de cada vez, durante Simulacao.Duracao e no
+// ritmo total de Simulacao.OpsPorSegundo. Ctrl+C encerra o banco atual e
+// pula para o relatório.
+//
+// Os PDVs, caixas e produtos são regenerados a partir da semente, então a
+// simulação deve usar a mesma configuração da geração. Clientes e produtos
+// seguem a mesma popularidade das notas geradas. As notas abertas recebem
+// seq_nota a partir do maior já gravado em cada banco, o que permite repetir
+// a simulação, e são gravadas só nas tabelas normalizadas. O num_nota de
+// cada PDV também continua do maior já gravado, dentro da faixa do PDV. Os
+// maiores números vêm dos metadados, que a simulação atualiza ao terminar.
+func executarSimulacao(args []string) {
+	cfg, ok := configurar("simulate", args)
+	if !ok {
+		return
+	}
+	ctx, cancelar := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelar()
+
+	pdvs, caixas, sorteadores := regenerarDimensoes(ctx, &cfg)
+
+	for _, banco := range criarOLTPs(&cfg) {
+		if err := banco.Abrir(ctx); err != nil {
+			log.Fatalf("Erro ao conectar ao %s: %v", banco.Nome(), err)
+		}
+
+		metadados, err := banco.Metadados(ctx)
+		if err != nil {
+			log.Fatalf("Erro ao ler os metadados da geração no %s (gere ou carregue os dados de novo): %v", banco.Nome(), err)
+		}
+		maioresNumNota, err := metadados.maioresNumNota()
+		if err != nil {
+			log.Fatalf("Erro nos metadados do %s: %v", banco.Nome(), err)
+		}
+		numeracao := novaNumeracaoNotas(pdvs, maioresNumNota)
+
+		fmt.Printf("\nSimulando %d PDVs no %s por %v (%d ops/s)...\n",
+			cfg.Simulacao.PDVs, banco.Nome(), cfg.Simulacao.Duracao, cfg.Simulacao.OpsPorSegundo)
+		medicoes, maiorSeqNota := simular(ctx, &cfg, banco, pdvs, caixas, sorteadores, metadados.MaiorSeqNota, numeracao)
+		medicoes.imprimir(banco.Nome())
+
+		// Mesmo depois de Ctrl+C, para a próxima simulação não repetir números
+		err = banco.GravarNumeracao(context.Background(), maiorSeqNota, numeracaoJSON(numeracao.maiores()))
+		if err != nil {
+			log.Fatalf("Erro ao gravar a numeração das notas no %s: %v", banco.Nome(), err)
+		}
+
+		if err := banco.Fechar(context.Background()); err != nil {
+			log.Printf("Erro ao fechar %s: %v", banco.Nome(), err)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// regenerarDimensoes gera de novo, em memória, os PDVs e caixas da semente
+// configurada, ordenados pelo código, e os produtos de que dependem os
+// sorteadores de popularidade.
+func regenerarDimensoes(ctx context.Context, cfg *Config) ([]PDV, map[int][]Caixa, *sorteadoresNotas) {
+	memoria := novoMemoriaSink()
+	saida := novaSaida(cfg, []Sink{memoria})
+	dim := &Dimensoes{}
+	gerarPromocoes(ctx, cfg, saida, dim)
+	gerarProdutos(ctx, cfg, saida, dim)
+	gerarPDVs(ctx, cfg, saida, dim)
+	gerarCaixas(ctx, cfg, saida, dim)
+
+	var pdvs []PDV
+	for _, r := range memoria.registros[PDV{}.Tabela()] {
+		pdvs = append(pdvs, r.(PDV))
+	}
+	slices.SortFunc(pdvs, func(a, b PDV) int { return cmp.Compare(a.CodPDV, b.CodPDV) })
+
+	// Caixas em serviço por loja, também em ordem de código
+	caixas := make(map[int][]Caixa)
+	for _, r := range memoria.registros[Caixa{}.Tabela()] {
+		if c := r.(Caixa); c.FlgFerias == "N" {
+			caixas[c.CodLoja] = append(caixas[c.CodLoja], c)
+		}
+	}
+	for _, lista := range caixas {
+		slices.SortFunc(lista, func(a, b Caixa) int { return cmp.Compare(a.CodCaixa, b.CodCaixa) })
+	}
+	return pdvs, caixas, novosSorteadoresNotas(cfg, dim)
+}
+
+// simular roda os PDVs virtuais contra um banco até o fim da duração. As
+// notas abertas recebem seq_nota a partir de maiorSeqNota+1 e num_nota de
+// numeracao. Devolve também o último seq_nota usado.
+func simular(ctx context.Context, cfg *Config, banco OLTP, pdvs []PDV, caixas map[int][]Caixa, sorteadores *sorteadoresNotas, maiorSeqNota int, numeracao *numeracaoNotas) (*medicoesOLTP, int) {
+	ctx, cancelar := context.WithTimeout(ctx, cfg.Simulacao.Duracao)
+	defer cancelar()
+
+	// Um único ticker limita o ritmo somado de todos os PDVs
+	ritmo := time.NewTicker(time.Second / time.Duration(cfg.Simulacao.OpsPorSegundo))
+	defer ritmo.Stop()
+
+	medicoes := &medicoesOLTP{
+		amostras: make(map[string][]time.Duration),
+		erros:    make(map[string]int),
+		primeiro: make(map[string]error),
+	}
+	var proximaNota atomic.Int64
+	proximaNota.Store(int64(maiorSeqNota))
+
+	inicio := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Simulacao.PDVs; i++ {
+		// Sem caixa em serviço na loja, o PDV não tem quem emita a nota
+		pdv := pdvs[i%len(pdvs)]
+		if len(caixas[pdv.CodLoja]) == 0 {
+			fmt.Printf("PDV virtual %d parado: a loja %d do PDV %d não tem caixa em serviço\n", i+1, pdv.CodLoja, pdv.CodPDV)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			virtual := &pdvVirtual{
+				cfg:         cfg,
+				banco:       banco,
+				pdv:         pdv,
+				caixas:      caixas[pdv.CodLoja],
+				sorteadores: sorteadores,
+				numeracao:   numeracao,
+				rng:         novoRand(cfg, "simulacao", i),
+				ritmo:       ritmo.C,
+				medicoes:    medicoes,
+			}
+			virtual.atender(ctx, &proximaNota)
+		}()
+	}
+	wg.Wait()
+	medicoes.duracao = time.Since(inicio)
+	return medicoes, int(proximaNota.Load())
+}
+
+// pdvVirtual é um PDV em atendimento. O fluxo aleatório de cada PDV vem da
+// semente, então os bancos recebem a mesma sequência de operações.
+type pdvVirtual struct {
+	cfg         *Config
+	banco       OLTP
+	pdv         PDV
+	caixas      []Caixa           // caixas em serviço da loja do PDV
+	sorteadores *sorteadoresNotas // clientes e produtos, pela popularidade
+	numeracao   *numeracaoNotas
+	rng         *rand.Rand
+	ritmo       <-chan time.Time
+	medicoes    *medicoesOLTP
+}
+
+// atender registra vendas em sequência até o contexto terminar ou a loja
+// ficar sem PDV vigente com faixa de num_nota livre.
+func (p *pdvVirtual) atender(ctx context.Context, proximaNota *atomic.Int64) {
+	for ctx.Err() == nil {
+		codCaixa := p.caixas[p.rng.Intn(len(p.caixas))].CodCaixa
+		codCliente := p.sorteadores.clientes.sortear(p.rng) + 1
+
+		datNota := time.Now().UTC()
+		codPDV, numNota, ok := p.numeracao.emitir(p.pdv.CodPDV, datNota)
+		if !ok {
+			return
+		}
+		nota := NotaFiscal{
+			SeqNota:    int(proximaNota.Add(1)),
+			CodPDV:     codPDV,
+			CodCaixa:   codCaixa,
+			CodCliente: codCliente,
+			NumNota:    numNota,
+			DatNota:    datNota,
+			FlgEntrega: "N",
+		}
+		if !p.medir(ctx, opAbrirNota, func() error { return p.banco.AbrirNota(ctx, nota) }) {
+			return
+		}
+
+		// Parte dos clientes se identifica no caixa
+		if p.rng.Intn(100) < p.cfg.Simulacao.PctBuscaCliente {
+			ok := p.medir(ctx, opBuscarCliente, func() error {
+				_, err := p.banco.BuscarCliente(ctx, codCliente)
+				return err
+			})
+			if !ok {
+				return
+			}
+		}
+
+		numItens := p.rng.Intn(p.cfg.Volumes.MaxItensPorNota) + 1
+		for j := 0; j < numItens; j++ {
+			codProduto := p.sorteadores.produtos.sortear(p.rng) + 1
+			qtdProduto := float64(p.rng.Intn(10) + 1)
+
+			var produto Produto
+			ok := p.medir(ctx, opLerProduto, func() error {
+				var err error
+				produto, err = p.banco.LerProduto(ctx, codProduto)
+				return err
+			})
+			if !ok {
+				return
+			}
+
+			item := ItemNotaFiscal{
+				SeqItemNota: j + 1,
+				SeqNota:     nota.SeqNota,
+				CodProduto:  codProduto,
+				QtdProduto:  qtdProduto,
+				VlrVenda:    produto.VlrVenda,
+				VlrCusto:    produto.VlrCusto,
+				VlrMedio:    produto.VlrMedio,
+			}
+			if !p.medir(ctx, opAdicionarItem, func() error { return p.banco.AdicionarItem(ctx, item) }) {
+				return
+			}
+			nota.VlrNota += item.VlrVenda * item.QtdProduto
+		}
+
+		vlrNota := float64(int(nota.VlrNota*100)) / 100
+		if !p.medir(ctx, opFecharNota, func() error { return p.banco.FecharNota(ctx, nota.SeqNota, vlrNota) }) {
+			return
+		}
+	}
+}
+
+// numeracaoNotas distribui os num_nota da simulação entre os PDVs virtuais,
+// que podem compartilhar o mesmo PDV. Cada PDV continua do maior número já
+// gravado no banco; esgotada a sua faixa ou encerrada a sua vigência, as
+// notas passam para os PDVs da mesma loja vigentes e com faixa livre, em
+// ordem de código, como na geração (ver redistribuirExcedentes).
+type numeracaoNotas struct {
+	mu        sync.Mutex
+	pdvs      map[int]PDV
+	porLoja   map[int][]int   // cod_loja -> cod_pdv, em ordem de código
+	proximo   map[int]float64 // cod_pdv -> próximo num_nota
+	esgotadas map[int]bool    // lojas sem PDV disponível, já avisadas
+}
+
+func novaNumeracaoNotas(pdvs []PDV, maioresNumNota map[int]float64) *numeracaoNotas {
+	n := &numeracaoNotas{
+		pdvs:      make(map[int]PDV, len(pdvs)),
+		porLoja:   make(map[int][]int),
+		proximo:   make(map[int]float64, len(pdvs)),
+		esgotadas: make(map[int]bool),
+	}
+	for _, p := range pdvs {
+		n.pdvs[p.CodPDV] = p
+		n.porLoja[p.CodLoja] = append(n.porLoja[p.CodLoja], p.CodPDV)
+		n.proximo[p.CodPDV] = p.NumNotaInicial
+		if maior, ok := maioresNumNota[p.CodPDV]; ok {
+			n.proximo[p.CodPDV] = max(p.NumNotaInicial, maior+1)
+		}
+	}
+	for _, lista := range n.porLoja {
+		slices.Sort(lista)
+	}
+	return n
+}
+
+// emitir devolve o PDV e o num_nota da próxima nota de codPDV, emitida em
+// datNota. ok é false quando nenhum PDV da loja está vigente em datNota com
+// números livres na faixa; como datNota só avança, isso não muda mais.
+func (n *numeracaoNotas) emitir(codPDV int, datNota time.Time) (pdv int, numNota float64, ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	codLoja := n.pdvs[codPDV].CodLoja
+	candidatos := append([]int{codPDV}, n.porLoja[codLoja]...)
+	for _, c := range candidatos {
+		p := n.pdvs[c]
+		vigente := !datNota.Before(p.DatInicioVigencia) && datNota.Before(p.DatFimVigencia)
+		if numNota := n.proximo[c]; vigente && numNota <= p.NumNotaFinal {
+			n.proximo[c]++
+			return c, numNota, true
+		}
+	}
+
+	if !n.esgotadas[codLoja] {
+		n.esgotadas[codLoja] = true
+		fmt.Printf("Loja %d sem PDV vigente com faixa de num_nota livre; os PDVs virtuais dela param\n", codLoja)
+	}
+	return 0, 0, false
+}
+
+// maiores devolve o maior num_nota emitido de cada PDV, contando os já
+// gravados antes da simulação.
+func (n *numeracaoNotas) maiores() map[int]float64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	maiores := make(map[int]float64)
+	for codPDV, proximo := range n.proximo {
+		if proximo > n.pdvs[codPDV].NumNotaInicial {
+			maiores[codPDV] = proximo - 1
+		}
+	}
+	return maiores
+}
+
+// medir espera a vez no ritmo global, executa a operação e registra sua
+// latência. Devolve false quando a simulação terminou; operações
+// interrompidas pelo fim da simulação não entram nas medições.
+func (p *pdvVirtual) medir(ctx context.Context, operacao string, fn func() error) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-p.ritmo:
+	}
+
+	inicio := time.Now()
+	err := fn()
+	latencia := time.Since(inicio)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	p.medicoes.registrar(operacao, latencia, err)
+	return true
+}
+
+// medicoesOLTP reúne as latências de cada operação numa simulação.
+type medicoesOLTP struct {
+	mu       sync.Mutex
+	amostras map[string][]time.Duration
+	erros    map[string]int
+	primeiro map[string]error
+	duracao  time.Duration
+}
+
+func (m *medicoesOLTP) registrar(operacao string, latencia time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err != nil {
+		m.erros[operacao]++
+		if _, ok := m.primeiro[operacao]; !ok {
+			m.primeiro[operacao] = err
+		}
+		return
+	}
+	m.amostras[operacao] = append(m.amostras[operacao], latencia)
+}
+
+// imprimir mostra uma linha por operação, com latências em milissegundos.
+func (m *medicoesOLTP) imprimir(banco string) {
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%.2f", float64(d)/float64(time.Millisecond))
+	}
+
+	total := 0
+	fmt.Printf("\n%-16s %-10s %8s %8s %8s %8s %8s %8s %8s\n",
+		"Operação", "Banco", "ops", "erros", "min", "mediana", "p95", "p99", "max")
+	for _, op := range operacoesOLTP {
+		amostras := m.amostras[op]
+		total += len(amostras) + m.erros[op]
+		e := calcularEstatisticas(amostras)
+		fmt.Printf("%-16s %-10s %8d %8d %8s %8s %8s %8s %8s\n",
+			op, banco, len(amostras), m.erros[op], ms(e.Min), ms(e.Mediana), ms(e.P95), ms(e.P99), ms(e.Max))
+	}
+	fmt.Printf("\n%d operações em %v (%.1f ops/s)\n", total, m.duracao.Round(time.Second), float64(total)/m.duracao.Seconds())
+
+	for _, op := range operacoesOLTP {
+		if err, ok := m.primeiro[op]; ok {
+			fmt.Printf("Primeiro erro em %s: %v\n", op, err)
+		}
+	}
+}
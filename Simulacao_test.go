@@ -0,0 +1,92 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestNumeracaoNotas(t *testing.T) {
+	inicio, fim := dataFixa("2020-01-01"), dataFixa("2030-01-01")
+	pdvs := []PDV{
+		{CodPDV: 1, CodLoja: 1, NumNotaInicial: 10, NumNotaFinal: 12, DatInicioVigencia: inicio, DatFimVigencia: fim},
+		{CodPDV: 2, CodLoja: 1, NumNotaInicial: 50, NumNotaFinal: 51, DatInicioVigencia: inicio, DatFimVigencia: fim},
+		{CodPDV: 3, CodLoja: 2, NumNotaInicial: 1, NumNotaFinal: 100, DatInicioVigencia: inicio, DatFimVigencia: fim},
+		{CodPDV: 4, CodLoja: 3, NumNotaInicial: 1, NumNotaFinal: 100, DatInicioVigencia: inicio, DatFimVigencia: dataFixa("2025-01-01")},
+		{CodPDV: 5, CodLoja: 3, NumNotaInicial: 7, NumNotaFinal: 100, DatInicioVigencia: inicio, DatFimVigencia: fim},
+	}
+	datNota := dataFixa("2026-10-16")
+	type emissao struct {
+		codPDV  int
+		numNota float64
+	}
+
+	casos := []struct {
+		nome      string
+		maiores   map[int]float64 // maior num_nota já gravado por PDV
+		codPDV    int
+		esperadas []emissao // em ordem de emissão
+		esgota    bool      // a emissão seguinte falha
+	}{
+		{
+			nome:      "PDV sem notas começa na faixa",
+			codPDV:    3,
+			esperadas: []emissao{{3, 1}, {3, 2}},
+		},
+		{
+			nome:      "continua do maior número gravado",
+			maiores:   map[int]float64{3: 40},
+			codPDV:    3,
+			esperadas: []emissao{{3, 41}, {3, 42}},
+		},
+		{
+			nome:      "faixa esgotada passa para outro PDV da loja e depois para",
+			maiores:   map[int]float64{1: 11},
+			codPDV:    1,
+			esperadas: []emissao{{1, 12}, {2, 50}, {2, 51}},
+			esgota:    true,
+		},
+		{
+			nome:      "vigência encerrada passa para outro PDV da loja",
+			codPDV:    4,
+			esperadas: []emissao{{5, 7}, {5, 8}},
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			numeracao := novaNumeracaoNotas(pdvs, c.maiores)
+			for i, e := range c.esperadas {
+				codPDV, numNota, ok := numeracao.emitir(c.codPDV, datNota)
+				if !ok || codPDV != e.codPDV || numNota != e.numNota {
+					t.Fatalf("emissão %d = PDV %d, nota %g (ok %v); esperado PDV %d, nota %g",
+						i, codPDV, numNota, ok, e.codPDV, e.numNota)
+				}
+			}
+			if _, _, ok := numeracao.emitir(c.codPDV, datNota); ok == c.esgota {
+				t.Errorf("emissão depois das esperadas: ok = %v, esperado %v", ok, !c.esgota)
+			}
+		})
+	}
+}
+
+// Os maiores números devolvidos para os metadados incluem os gravados antes
+// da simulação e omitem os PDVs que nunca emitiram nota.
+func TestNumeracaoNotasMaiores(t *testing.T) {
+	vigencia := func(p PDV) PDV {
+		p.DatInicioVigencia, p.DatFimVigencia = dataFixa("2020-01-01"), dataFixa("2030-01-01")
+		return p
+	}
+	pdvs := []PDV{
+		vigencia(PDV{CodPDV: 1, CodLoja: 1, NumNotaInicial: 10, NumNotaFinal: 20}),
+		vigencia(PDV{CodPDV: 2, CodLoja: 1, NumNotaInicial: 50, NumNotaFinal: 60}),
+		vigencia(PDV{CodPDV: 3, CodLoja: 2, NumNotaInicial: 1, NumNotaFinal: 100}),
+	}
+	numeracao := novaNumeracaoNotas(pdvs, map[int]float64{2: 55})
+	for i := 0; i < 3; i++ {
+		numeracao.emitir(1, dataFixa("2026-10-16"))
+	}
+
+	esperados := map[int]float64{1: 12, 2: 55}
+	if got := numeracao.maiores(); !maps.Equal(got, esperados) {
+		t.Errorf("maiores = %v, esperado %v", got, esperados)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Nomes aceitos em --sinks
+const (
+	sinkMongoDB   = "mongodb"
+	sinkCassandra = "cassandra"
+	sinkPostgres  = "postgres"
+	sinkCSV       = "csv"
+	sinkJSONL     = "jsonl"
+	sinkBSON      = "bson"
+	sinkParquet   = "parquet"
+)
+
+var sinksConhecidos = []string{sinkMongoDB, sinkCassandra, sinkPostgres, sinkCSV, sinkJSONL, sinkBSON, sinkParquet}
+
+// tamanhoLoteSaida é o mínimo de registros de uma mesma tabela que cada
+// goroutine acumula antes de entregá-los aos sinks.
+const tamanhoLoteSaida = 1000
+
+// Registro é uma entidade gerada (Cidade, Produto, NotaFiscal...). As colunas
+// e os valores são lidos das tags bson da struct, que definem o nome de cada
+// campo em todos os destinos.
+type Registro interface {
+	Tabela() string
+}
+
+// Sink é um destino para os registros gerados.
+//
+// Escrever é chamado concorrentemente por várias goroutines, sempre com
+// registros de uma única tabela, e não deve reter o slice recebido depois
+// de retornar.
+type Sink interface {
+	Nome() string
+	Abrir(ctx context.Context) error
+	Escrever(ctx context.Context, tabela string, registros []Registro) error
+	Flush(ctx context.Context) error
+	Fechar(ctx context.Context) error
+}
+
+// SinkSeletivo é implementado pelos sinks que descartam algumas tabelas.
+type SinkSeletivo interface {
+	Aceita(r Registro) bool
+}
+
+// criarSinks instancia os sinks listados na configuração.
+func criarSinks(cfg *Config) ([]Sink, error) {
+	var sinks []Sink
+	for _, nome := range cfg.Sinks {
+		switch nome {
+		case sinkMongoDB:
+			sinks = append(sinks, novoMongoSink(cfg))
+		case sinkCassandra:
+			sinks = append(sinks, novoCassandraSink(cfg))
+		case sinkPostgres:
+			sinks = append(sinks, novoPostgresSink(cfg))
+		case sinkCSV:
+			sinks = append(sinks, novoCSVSink(cfg))
+		case sinkJSONL, sinkBSON:
+			sinks = append(sinks, novoMongoArquivoSink(cfg, nome))
+		case sinkParquet:
+			sinks = append(sinks, novoParquetSink(cfg))
+		default:
+			return nil, fmt.Errorf("sink desconhecido: %q", nome)
+		}
+	}
+	return sinks, nil
+}
+
+// Saida distribui os registros gerados para todos os sinks configurados.
+type Saida struct {
+	sinks       []Sink
+	tamanhoLote int
+}
+
+// novaSaida agrupa os sinks. O lote entregue a eles cobre o maior lote
+// configurado, para que nenhum sink receba lotes menores que o pedido.
+func novaSaida(cfg *Config, sinks []Sink) *Saida {
+	return &Saida{
+		sinks:       sinks,
+		tamanhoLote: max(tamanhoLoteSaida, cfg.Mongo.TamanhoLote),
+	}
+}
+
+func (s *Saida) abrir(ctx context.Context) error {
+	for i, sink := range s.sinks {
+		if err := sink.Abrir(ctx); err != nil {
+			// Fecha os que já foram abertos antes de desistir
+			for _, aberto := range s.sinks[:i] {
+				aberto.Fechar(ctx)
+			}
+			return fmt.Errorf("%s: %w", sink.Nome(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Saida) escrever(ctx context.Context, tabela string, registros []Registro) {
+	for _, sink := range s.sinks {
+		if err := sink.Escrever(ctx, tabela, registros); err != nil {
+			log.Printf("Erro ao inserir %s no %s: %v", tabela, sink.Nome(), err)
+		}
+	}
+}
+
+func (s *Saida) flush(ctx context.Context) error {
+	var erros []error
+	for _, sink := range s.sinks {
+		if err := sink.Flush(ctx); err != nil {
+			erros = append(erros, fmt.Errorf("%s: %w", sink.Nome(), err))
+		}
+	}
+	return errors.Join(erros...)
+}
+
+func (s *Saida) fechar(ctx context.Context) {
+	for _, sink := range s.sinks {
+		if err := sink.Fechar(ctx); err != nil {
+			log.Printf("Erro ao fechar %s: %v", sink.Nome(), err)
+		}
+	}
+}
+
+// novoLote cria um acumulador de registros para uma goroutine.
+func (s *Saida) novoLote(ctx context.Context) *Lote {
+	return &Lote{ctx: ctx, saida: s, pendentes: make(map[string][]Registro)}
+}
+
+// Lote acumula os registros produzidos por uma goroutine e os entrega à
+// Saida agrupados por tabela. Quando uma tabela atinge o tamanho do lote,
+// todas as pendentes são descarregadas na ordem em que apareceram, de modo
+// que uma nota sempre chega aos sinks antes dos seus itens.
+type Lote struct {
+	ctx       context.Context
+	saida     *Saida
+	ordem     []string
+	pendentes map[string][]Registro
+}
+
+// Adicionar inclui um registro no lote.
+func (l *Lote) Adicionar(r Registro) {
+	tabela := r.Tabela()
+	pendentes, ok := l.pendentes[tabela]
+	if !ok {
+		l.ordem = append(l.ordem, tabela)
+	}
+
+	l.pendentes[tabela] = append(pendentes, r)
+	if len(l.pendentes[tabela]) >= l.saida.tamanhoLote {
+		l.Descarregar()
+	}
+}
+
+// Descarregar entrega aos sinks tudo o que está pendente.
+func (l *Lote) Descarregar() {
+	for _, tabela := range l.ordem {
+		registros := l.pendentes[tabela]
+		if len(registros) == 0 {
+			continue
+		}
+		l.saida.escrever(l.ctx, tabela, registros)
+		l.pendentes[tabela] = registros[:0]
+	}
+}
+
+// Valores da tag chave
+const (
+	chaveParticao   = "particao"
+	chaveClustering = "clustering"
+)
+
+// campoRegistro descreve um campo de struct mapeado para uma coluna.
+type campoRegistro struct {
+	coluna   string
+	indice   int
+	chave    string // chaveParticao, chaveClustering ou vazio
+	opcional bool   // bson omitempty
+}
+
+// prefixoValorMonetario identifica as colunas de valores em reais, que os
+// destinos com tipo decimal gravam com centavos exatos.
+const prefixoValorMonetario = "vlr_"
+
+var camposPorTipo sync.Map // reflect.Type -> []campoRegistro
+
+// camposDe devolve, em ordem de declaração, os campos com tag bson do tipo
+// do registro. O resultado é guardado em cache por tipo.
+func camposDe(t reflect.Type) []campoRegistro {
+	if campos, ok := camposPorTipo.Load(t); ok {
+		return campos.([]campoRegistro)
+	}
+
+	var campos []campoRegistro
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("bson")
+		if !ok || tag == "-" {
+			continue
+		}
+		nome, opcoes, _ := strings.Cut(tag, ",")
+		campos = append(campos, campoRegistro{
+			coluna:   nome,
+			indice:   i,
+			chave:    t.Field(i).Tag.Get("chave"),
+			opcional: strings.Contains(opcoes, "omitempty"),
+		})
+	}
+
+	camposPorTipo.Store(t, campos)
+	return campos
+}
+
+// colunasDe devolve os nomes das colunas de um registro.
+func colunasDe(r Registro) []string {
+	campos := camposDe(reflect.TypeOf(r))
+	colunas := make([]string, len(campos))
+	for i, c := range campos {
+		colunas[i] = c.coluna
+	}
+	return colunas
+}
+
+// valoresDe devolve os valores de um registro na mesma ordem de colunasDe.
+func valoresDe(r Registro) []any {
+	v := reflect.ValueOf(r)
+	campos := camposDe(v.Type())
+	valores := make([]any, len(campos))
+	for i, c := range campos {
+		valores[i] = v.Field(c.indice).Interface()
+	}
+	return valores
+}
+
+// chaveDe descreve a chave primária de um registro ("seq_nota=7 seq_item_nota=2"),
+// para identificá-lo em mensagens de erro.
+func chaveDe(r Registro) string {
+	v := reflect.ValueOf(r)
+	var partes []string
+	for _, c := range camposDe(v.Type()) {
+		if c.chave != "" {
+			partes = append(partes, fmt.Sprintf("%s=%v", c.coluna, v.Field(c.indice).Interface()))
+		}
+	}
+	return strings.Join(partes, " ")
+}
+
+// maxFalhasExibidas limita quantas falhas individuais ErroLote descreve.
+const maxFalhasExibidas = 5
+
+// FalhaRegistro é um registro de um lote que o sink não conseguiu gravar.
+type FalhaRegistro struct {
+	Chave string
+	Err   error
+}
+
+// ErroLote relata quais registros de um lote não foram gravados.
+type ErroLote struct {
+	Tabela string
+	Total  int
+	Falhas []FalhaRegistro
+}
+
+func (e *ErroLote) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d de %d registros de %s falharam", len(e.Falhas), e.Total, e.Tabela)
+	for i, f := range e.Falhas {
+		if i == maxFalhasExibidas {
+			fmt.Fprintf(&b, "; e mais %d", len(e.Falhas)-i)
+			break
+		}
+		fmt.Fprintf(&b, "; [%s] %v", f.Chave, f.Err)
+	}
+	return b.String()
+}
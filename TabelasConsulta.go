@@ -0,0 +1,82 @@
+package main
+
+import "time"
+
+// Tabelas de consulta do Cassandra (modelagem query-first). Cada uma atende
+// uma das consultas do README e já traz, gravados junto de cada linha, os
+// dados que no MongoDB vêm de $lookup: nome do produto, cidade e estado do
+// cliente e setor do produto.
+
+// TabelaConsulta marca os registros que só existem no Cassandra. Os sinks
+// de bancos com junções (MongoDB) os ignoram.
+type TabelaConsulta interface {
+	Registro
+	tabelaConsulta()
+}
+
+// Consulta 2: produtos mais vendidos
+type ItemNotaFiscalPorProduto struct {
+	ProdutoNome string  `bson:"produto_nome" chave:"particao"`
+	SeqNota     int     `bson:"seq_nota" chave:"clustering"`
+	SeqItemNota int     `bson:"seq_item_nota" chave:"clustering"`
+	CodProduto  int     `bson:"cod_produto"`
+	QtdProduto  float64 `bson:"qtd_produto"`
+	VlrVenda    float64 `bson:"vlr_venda"`
+}
+
+// Consulta 3: faturamento por estado (do endereço do cliente)
+type NotaFiscalPorEstado struct {
+	Estado  string    `bson:"estado" chave:"particao"`
+	SeqNota int       `bson:"seq_nota" chave:"clustering"`
+	DatNota time.Time `bson:"dat_nota"`
+	VlrNota float64   `bson:"vlr_nota"`
+}
+
+// Consulta 4: clientes fidelizados por cidade
+type ClienteFidelizadoPorCidade struct {
+	Cidade     string `bson:"cidade" chave:"particao"`
+	CodCliente int    `bson:"cod_cliente" chave:"clustering"`
+	Estado     string `bson:"estado"`
+	NomCliente string `bson:"nom_cliente"`
+}
+
+// Consulta 5: lucro médio por setor. VlrLucro evita depender de aritmética
+// no SELECT, que só existe a partir do Cassandra 4.0.
+type ItemNotaFiscalPorSetor struct {
+	Setor       string  `bson:"setor" chave:"particao"`
+	SeqNota     int     `bson:"seq_nota" chave:"clustering"`
+	SeqItemNota int     `bson:"seq_item_nota" chave:"clustering"`
+	CodProduto  int     `bson:"cod_produto"`
+	VlrVenda    float64 `bson:"vlr_venda"`
+	VlrCusto    float64 `bson:"vlr_custo"`
+	VlrLucro    float64 `bson:"vlr_lucro"`
+}
+
+// Consulta 6: cliente pelo CPF. O CPF é único, então cada partição tem um
+// só cliente.
+type ClientePorCPF struct {
+	NumCPF     string `bson:"num_cpf" chave:"particao"`
+	CodCliente int    `bson:"cod_cliente"`
+	NomCliente string `bson:"nom_cliente"`
+}
+
+func (ItemNotaFiscalPorProduto) Tabela() string   { return "item_nota_fiscal_por_produto" }
+func (NotaFiscalPorEstado) Tabela() string        { return "nota_fiscal_por_estado" }
+func (ClienteFidelizadoPorCidade) Tabela() string { return "cliente_fidelizado_por_cidade" }
+func (ItemNotaFiscalPorSetor) Tabela() string     { return "item_nota_fiscal_por_setor" }
+func (ClientePorCPF) Tabela() string              { return "cliente_por_cpf" }
+
+func (ItemNotaFiscalPorProduto) tabelaConsulta()   {}
+func (NotaFiscalPorEstado) tabelaConsulta()        {}
+func (ClienteFidelizadoPorCidade) tabelaConsulta() {}
+func (ItemNotaFiscalPorSetor) tabelaConsulta()     {}
+func (ClientePorCPF) tabelaConsulta()              {}
+
+// tabelasConsulta tem um modelo de cada tabela de consulta, como tabelas.
+var tabelasConsulta = []Registro{
+	ItemNotaFiscalPorProduto{},
+	NotaFiscalPorEstado{},
+	ClienteFidelizadoPorCidade{},
+	ItemNotaFiscalPorSetor{},
+	ClientePorCPF{},
+}